@@ -26,6 +26,13 @@ type ED struct {
 
 // implement the interface
 func (ed ED) EvoDct(Ra []float64) float64 {
+	// with the number of candidates tried and the variance of their Sharpe
+	// ratios, the deflated Sharpe ratio corrects for the search itself,
+	// a probability already in [0, 1]; without both the plain Sharpe ratio is used
+	if trials, variance, ok := trialsPars(ed.Pars); ok {
+		return statistics.DeflatedSharpeRatio(Ra, 0.035/12, trials, variance)
+	}
+
 	tmpRes := statistics.SharpeRatio(Ra, 0.035/12, 12, true)
 
 	// try get target return from Pars
@@ -37,6 +44,33 @@ func (ed ED) EvoDct(Ra []float64) float64 {
 	return sigmoid(tmpRes,1.0)
 }
 
+// read the multiple testing parameters of the deflated Sharpe ratio:
+// Trials (int, int64 or a whole float64) and TrialsVariance (float64), or
+// TrialSharpes ([]float64, the per period Sharpe ratios of the candidates)
+// which gives the variance, and the number of trials when Trials is missing
+func trialsPars(pars map[string]interface{}) (trials int, variance float64, ok bool) {
+	switch v := pars["Trials"].(type) {
+	case int:
+		trials = v
+	case int64:
+		trials = int(v)
+	case float64:
+		if v == math.Trunc(v) {
+			trials = int(v)
+		}
+	}
+	variance, ok = pars["TrialsVariance"].(float64)
+	if sharpes, found := pars["TrialSharpes"].([]float64); found {
+		if !ok {
+			variance, ok = statistics.TrialsVariance(sharpes), true
+		}
+		if trials == 0 {
+			trials = len(sharpes)
+		}
+	}
+	return trials, variance, ok && trials > 0 && variance >= 0
+}
+
 // for instance: sigmoid function
 func sigmoid(x float64, scale float64) float64 {
 	return 1 / (1 + math.Exp(-x/scale))
@@ -72,6 +106,9 @@ func main(){
 	// use a struct to implement the interface
 	tmpED := ED{}
 	useInterface(tmpED, rt)
+	// the same candidate as the best of 1000 tried
+	dsrED := ED{Pars: map[string]interface{}{"Trials": 1000, "TrialsVariance": 0.0025}}
+	useInterface(dsrED, rt)
 
 }
 
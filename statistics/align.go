@@ -0,0 +1,134 @@
+package statistics
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// - define how two date indexes are joined
+type JoinType int
+
+const (
+	// InnerJoin keeps only the dates present in both series
+	InnerJoin JoinType = iota
+	// OuterJoin keeps every date of either series and fills the gaps
+	OuterJoin
+)
+
+// - define how the gaps of an outer join are filled
+type FillPolicy int
+
+const (
+	// FillZero treats a missing return as a flat period
+	FillZero FillPolicy = iota
+	// FillForward repeats the last known return of the series
+	FillForward
+)
+
+// - define the result of an alignment
+type AlignResult struct {
+	// Ra and Rb share the same dates
+	Ra *ReturnSeries
+	Rb *ReturnSeries
+	// DroppedA and DroppedB count the observations of each input left out
+	DroppedA int
+	DroppedB int
+	// FilledA and FilledB count the values created by the fill policy
+	FilledA int
+	FilledB int
+}
+
+// Align matches two date indexed series before a relative metric runs
+// with an outer join, dates that cannot be filled (a forward fill before
+// the first observation of a series) are dropped and counted as well
+func Align(a, b *ReturnSeries, join JoinType, fill FillPolicy) (*AlignResult, error) {
+	if a == nil || b == nil {
+		return nil, errors.New("cannot align a nil series")
+	}
+	n := len(a.Values) + len(b.Values)
+	dates := make([]time.Time, 0, n)
+	va := make([]float64, 0, n)
+	vb := make([]float64, 0, n)
+
+	i, j := 0, 0
+	for i < len(a.Dates) || j < len(b.Dates) {
+		switch {
+		case j == len(b.Dates) || (i < len(a.Dates) && a.Dates[i].Before(b.Dates[j])):
+			dates = append(dates, a.Dates[i])
+			va = append(va, a.Values[i])
+			vb = append(vb, math.NaN())
+			i++
+		case i == len(a.Dates) || b.Dates[j].Before(a.Dates[i]):
+			dates = append(dates, b.Dates[j])
+			va = append(va, math.NaN())
+			vb = append(vb, b.Values[j])
+			j++
+		default:
+			dates = append(dates, a.Dates[i])
+			va = append(va, a.Values[i])
+			vb = append(vb, b.Values[j])
+			i++
+			j++
+		}
+	}
+
+	// remember which values are created by the fill policy
+	missA := missing(va)
+	missB := missing(vb)
+	if join == OuterJoin {
+		fillGaps(va, fill)
+		fillGaps(vb, fill)
+	}
+
+	// keep the rows where both values are known
+	res := &AlignResult{}
+	k := 0
+	for r := range dates {
+		if math.IsNaN(va[r]) || math.IsNaN(vb[r]) {
+			continue
+		}
+		if missA[r] {
+			res.FilledA++
+		}
+		if missB[r] {
+			res.FilledB++
+		}
+		dates[k], va[k], vb[k] = dates[r], va[r], vb[r]
+		k++
+	}
+	dates, va, vb = dates[:k], va[:k], vb[:k]
+	res.DroppedA = len(a.Values) - (k - res.FilledA)
+	res.DroppedB = len(b.Values) - (k - res.FilledB)
+
+	res.Ra = &ReturnSeries{Name: a.Name, Dates: dates, Values: va, Periodicity: a.Periodicity}
+	res.Rb = &ReturnSeries{Name: b.Name, Dates: dates, Values: vb, Periodicity: b.Periodicity}
+	return res, nil
+}
+
+// * function to mark the NaN of a column
+func missing(v []float64) []bool {
+	mask := make([]bool, len(v))
+	for i := range v {
+		mask[i] = math.IsNaN(v[i])
+	}
+	return mask
+}
+
+// * function to fill the NaN of a column in place
+// a forward fill leaves the leading NaN untouched
+func fillGaps(v []float64, fill FillPolicy) {
+	last := math.NaN()
+	for i := range v {
+		if !math.IsNaN(v[i]) {
+			last = v[i]
+			continue
+		}
+		switch fill {
+		case FillZero:
+			v[i] = 0
+		case FillForward:
+			v[i] = last
+		}
+	}
+}
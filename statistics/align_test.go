@@ -0,0 +1,110 @@
+package statistics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test Align on the managers series
+func TestAlign(t *testing.T) {
+	rf, err := ReadFrame("../data/managers.csv")
+	assert.Nil(t, err)
+	ham2, _ := rf.Series("HAM2")
+	sp, _ := rf.Series("SP500 TR")
+
+	// inner join drops the months before HAM2 starts
+	res, err := Align(ham2, sp, InnerJoin, FillZero)
+	assert.Nil(t, err)
+	assert.Equal(t, 125, res.Ra.Len())
+	assert.Equal(t, res.Ra.Dates, res.Rb.Dates)
+	assert.Equal(t, 0, res.DroppedA)
+	assert.Equal(t, 7, res.DroppedB)
+	// same number as the StringToFloatSliceBench test
+	assert.InDelta(t, 0.07759873, ActivePremium(res.Ra.Values, res.Rb.Values, 12, true), 0.0000001)
+	ap, err := ham2.ActivePremium(sp, true)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.07759873, ap, 0.0000001)
+
+	// outer join with zero fill keeps every benchmark month
+	res, err = Align(ham2, sp, OuterJoin, FillZero)
+	assert.Nil(t, err)
+	assert.Equal(t, 132, res.Ra.Len())
+	assert.Equal(t, 7, res.FilledA)
+	assert.Equal(t, 0, res.DroppedA)
+	assert.Equal(t, 0, res.DroppedB)
+	assert.Equal(t, 0.0, res.Ra.Values[0])
+
+	// forward fill cannot fill before the first observation
+	res, err = Align(ham2, sp, OuterJoin, FillForward)
+	assert.Nil(t, err)
+	assert.Equal(t, 125, res.Ra.Len())
+	assert.Equal(t, 7, res.DroppedB)
+
+	// a benchmark with holes and shifted dates
+	d := func(m int) time.Time { return time.Date(2020, time.Month(m+1), 0, 0, 0, 0, 0, time.UTC) }
+	a, _ := NewReturnSeries([]time.Time{d(1), d(2), d(3), d(4)}, []float64{0.01, 0.02, 0.03, 0.04})
+	b, _ := NewReturnSeries([]time.Time{d(2), d(4), d(5)}, []float64{0.1, 0.2, 0.3})
+	res, err = Align(a, b, InnerJoin, FillZero)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.02, 0.04}, res.Ra.Values)
+	assert.Equal(t, []float64{0.1, 0.2}, res.Rb.Values)
+	assert.Equal(t, 2, res.DroppedA)
+	assert.Equal(t, 1, res.DroppedB)
+	res, err = Align(a, b, OuterJoin, FillForward)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.02, 0.03, 0.04, 0.04}, res.Ra.Values)
+	assert.Equal(t, []float64{0.1, 0.1, 0.2, 0.3}, res.Rb.Values)
+	assert.Equal(t, 1, res.DroppedA)
+	assert.Equal(t, 1, res.FilledA)
+	assert.Equal(t, 1, res.FilledB)
+
+	_, err = Align(a, nil, InnerJoin, FillZero)
+	assert.NotNil(t, err)
+
+	// the relative metrics report a failed or an empty alignment
+	_, err = a.CAPM(nil)
+	assert.NotNil(t, err)
+	c, _ := NewReturnSeries([]time.Time{d(7), d(8)}, []float64{0.1, 0.2})
+	_, err = a.TrackingError(c)
+	assert.NotNil(t, err)
+	_, err = a.InformationRatio(c)
+	assert.NotNil(t, err)
+
+	// a risk free series is restricted to the common dates
+	cash, _ := NewReturnSeries([]time.Time{d(1), d(2), d(3), d(4)}, []float64{0.001, 0.002, 0.003, 0.004})
+	kept, err := a.alignRf(cash, []int{1, 3})
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.002, 0.004}, kept)
+	kept, err = a.alignRf(cash.Values, []int{1, 3})
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.002, 0.004}, kept)
+	_, err = a.alignRf(cash.Values[1:], []int{1, 3})
+	var lme *LengthMismatchError
+	assert.ErrorAs(t, err, &lme)
+	_, err = a.alignRf(cash.Trailing(1), []int{1, 3})
+	assert.NotNil(t, err)
+}
+
+// Test the market timing of a series with a risk free series on other dates
+func TestSeriesMarketTiming(t *testing.T) {
+	frame, err := ReadFrame("../data/managers.csv")
+	assert.Nil(t, err)
+	ham2, _ := frame.Series("HAM2")
+	sp, _ := frame.Series("SP500 TR")
+	rf, _ := frame.Series("US 3m TR")
+
+	// HAM2 starts in August 1996, the risk free series in January
+	res, _ := Align(ham2, sp, InnerJoin, FillZero)
+	rfKept := rf.Window(ham2.Start(), ham2.End()).Values
+	alpha, beta, gamma := MarketTiming(res.Ra.Values, res.Rb.Values, rfKept, "TM")
+	a, b, g, err := ham2.MarketTiming(sp, rf, "TM")
+	assert.Nil(t, err)
+	assert.Equal(t, alpha, a)
+	assert.Equal(t, beta, b)
+	assert.Equal(t, gamma, g)
+
+	_, _, _, err = ham2.MarketTiming(sp, rf.Values, "TM")
+	assert.NotNil(t, err)
+}
@@ -1,6 +1,11 @@
 package statistics
 
-import "gonum.org/v1/gonum/stat"
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
 
 // use gonum package to implement
 
@@ -50,25 +55,302 @@ func (c *CAPM) Alpha(rf interface{}) float64 {
 	return stat.Mean(excessRa, nil) - beta*stat.Mean(excessRb, nil)
 }
 
-// - Method for TimingRatio
-func (c *CAPM) TimingRatio() float64 {
-	// sort out the positive and negative returns into different slices
-	positiveRa := make([]float64, 0)
-	negativeRa := make([]float64, 0)
-	positiveRb := make([]float64, 0)
-	negativeRb := make([]float64, 0)
-	for i, val := range c.Rb {
+// * struct for the periods split by the sign of the benchmark return
+// up holds the periods with Rb > 0 and down the others
+type marketSplit struct {
+	upRa   []float64
+	upRb   []float64
+	downRa []float64
+	downRb []float64
+}
+
+// * method to sort out the up and down market periods into different slices
+func (c *CAPM) split() marketSplit {
+	return splitMarket(c.Ra, c.Rb)
+}
+
+// * function to split ra and rb on the sign of rb
+func splitMarket(ra, rb []float64) marketSplit {
+	s := marketSplit{
+		upRa:   make([]float64, 0),
+		upRb:   make([]float64, 0),
+		downRa: make([]float64, 0),
+		downRb: make([]float64, 0),
+	}
+	for i, val := range rb {
 		if val > 0 {
-			positiveRb = append(positiveRb, val)
-			positiveRa = append(positiveRa, c.Ra[i])
+			s.upRb = append(s.upRb, val)
+			s.upRa = append(s.upRa, ra[i])
 		} else {
-			negativeRb = append(negativeRb, val)
-			negativeRa = append(negativeRa, c.Ra[i])
+			s.downRb = append(s.downRb, val)
+			s.downRa = append(s.downRa, ra[i])
 		}
 	}
+	return s
+}
+
+// * method to split the excess returns over rf on the sign of the benchmark
+// excess return, as CAPM.beta.bull and CAPM.beta.bear
+func (c *CAPM) splitExcess(rf interface{}) marketSplit {
+	return splitMarket(c.excess(rf))
+}
+
+// * function for the beta of a sub sample
+func subBeta(ra, rb []float64) float64 {
+	return CoVariance(ra, rb) / Variance(rb)
+}
+
+// - Method for BetaBull, the beta over the up market periods
+// the excess returns over rf are regressed on the periods where Rb - rf > 0
+func (c *CAPM) BetaBull(rf interface{}) float64 {
+	s := c.splitExcess(rf)
+	return subBeta(s.upRa, s.upRb)
+}
+
+// - Method for BetaBear, the beta over the down market periods
+// the excess returns over rf are regressed on the periods where Rb - rf <= 0
+func (c *CAPM) BetaBear(rf interface{}) float64 {
+	s := c.splitExcess(rf)
+	return subBeta(s.downRa, s.downRb)
+}
+
+// - Method for TimingRatio
+// the bull beta over the bear beta, both of the excess returns over rf
+func (c *CAPM) TimingRatio(rf interface{}) float64 {
 	// give betas to the positive and negative returns
-	betaPositive := CoVariance(positiveRa, positiveRb) / Variance(positiveRb)
-	betaNegative := CoVariance(negativeRa, negativeRb) / Variance(negativeRb)
+	s := c.splitExcess(rf)
+	betaPositive := subBeta(s.upRa, s.upRb)
+	betaNegative := subBeta(s.downRa, s.downRb)
 	// calculate the timing ratio
 	return betaPositive / betaNegative
 }
+
+// - Method for UpCaptureRatio
+// mean return over the mean benchmark return in the up market periods
+func (c *CAPM) UpCaptureRatio() float64 {
+	s := c.split()
+	return stat.Mean(s.upRa, nil) / stat.Mean(s.upRb, nil)
+}
+
+// - Method for DownCaptureRatio
+// mean return over the mean benchmark return in the down market periods
+func (c *CAPM) DownCaptureRatio() float64 {
+	s := c.split()
+	return stat.Mean(s.downRa, nil) / stat.Mean(s.downRb, nil)
+}
+
+// * function for the share of the periods where ra beats the threshold
+// beats compares the return of a period with the benchmark return
+func hitRatio(ra, rb []float64, beats func(ra, rb float64) bool) float64 {
+	hits := 0
+	for i := range ra {
+		if beats(ra[i], rb[i]) {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(ra))
+}
+
+// - Method for UpNumberRatio
+// share of the up market periods where the portfolio is also up
+func (c *CAPM) UpNumberRatio() float64 {
+	s := c.split()
+	return hitRatio(s.upRa, s.upRb, func(ra, _ float64) bool { return ra > 0 })
+}
+
+// - Method for DownNumberRatio
+// share of the down market periods (Rb <= 0) where the portfolio is also down
+func (c *CAPM) DownNumberRatio() float64 {
+	s := c.split()
+	return hitRatio(s.downRa, s.downRb, func(ra, _ float64) bool { return ra < 0 })
+}
+
+// - Method for UpPercentRatio
+// share of the up market periods where the portfolio beats the benchmark
+func (c *CAPM) UpPercentRatio() float64 {
+	s := c.split()
+	return hitRatio(s.upRa, s.upRb, func(ra, rb float64) bool { return ra > rb })
+}
+
+// - Method for DownPercentRatio
+// share of the down market periods (Rb <= 0) where the portfolio beats the benchmark
+func (c *CAPM) DownPercentRatio() float64 {
+	s := c.split()
+	return hitRatio(s.downRa, s.downRb, func(ra, rb float64) bool { return ra > rb })
+}
+
+// - Method for BattingAverage
+// share of all the periods where the portfolio beats the benchmark
+func (c *CAPM) BattingAverage() float64 {
+	hits := 0
+	for i, rb := range c.Rb {
+		if c.Ra[i] > rb {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(c.Rb))
+}
+
+// * method for the excess returns of Ra and Rb over rf
+func (c *CAPM) excess(rf interface{}) (excessRa, excessRb []float64) {
+	ra := ReturnsCalculator{c.Ra}
+	rb := ReturnsCalculator{c.Rb}
+	return ra.Excess(rf), rb.Excess(rf)
+}
+
+// * method for the intercept and the slope of the regression of Ra - rf on Rb - rf
+// the alpha and beta of table.CAPM; Beta and Alpha keep the beta of the raw returns
+func (c *CAPM) regression(rf interface{}) (alpha, beta float64) {
+	excessRa, excessRb := c.excess(rf)
+	beta = CoVariance(excessRa, excessRb) / Variance(excessRb)
+	alpha = stat.Mean(excessRa, nil) - beta*stat.Mean(excessRb, nil)
+	return alpha, beta
+}
+
+// * function for the annualized geometric risk-free rate
+// rf is a float64 per period rate or a []float64 with one rate per observation
+func annualizedRf(rf interface{}, scale int) float64 {
+	switch v := rf.(type) {
+	case float64:
+		return math.Pow(1+v, float64(scale)) - 1
+	case []float64:
+		return AnnualizedReturn(v, scale, true)
+	}
+	return math.NaN()
+}
+
+// - Method for the Correlation of the excess returns
+func (c *CAPM) Correlation(rf interface{}) float64 {
+	excessRa, excessRb := c.excess(rf)
+	return Correlation(excessRa, excessRb)
+}
+
+// - Method for the p value of the correlation
+// two sided t test of a zero correlation with n-2 degrees of freedom
+func (c *CAPM) CorrelationPValue(rf interface{}) float64 {
+	r := c.Correlation(rf)
+	df := float64(len(c.Ra) - 2)
+	t := r * math.Sqrt(df/(1-r*r))
+	dist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}
+	return 2 * (1 - dist.CDF(math.Abs(t)))
+}
+
+// - Method for the RSquared of the regression
+func (c *CAPM) RSquared(rf interface{}) float64 {
+	r := c.Correlation(rf)
+	return r * r
+}
+
+// - Method for SystematicRisk
+// the excess return beta times the annualized standard deviation of the benchmark excess returns
+func (c *CAPM) SystematicRisk(rf interface{}, scale int) float64 {
+	_, excessRb := c.excess(rf)
+	_, beta := c.regression(rf)
+	return beta * StdDevAnnualized(excessRb, scale)
+}
+
+// - Method for SpecificRisk
+// annualized standard deviation (1/n) of the regression residuals
+func (c *CAPM) SpecificRisk(rf interface{}, scale int) float64 {
+	excessRa, excessRb := c.excess(rf)
+	alpha, beta := c.regression(rf)
+	epsilon := make([]float64, len(excessRa))
+	for i := range excessRa {
+		epsilon[i] = excessRa[i] - alpha - beta*excessRb[i]
+	}
+	return math.Sqrt(centralMoment(epsilon, 2) * float64(scale))
+}
+
+// - Method for TotalRisk
+// the square root of the systematic and specific variances
+func (c *CAPM) TotalRisk(rf interface{}, scale int) float64 {
+	sys := c.SystematicRisk(rf, scale)
+	spec := c.SpecificRisk(rf, scale)
+	return math.Sqrt(sys*sys + spec*spec)
+}
+
+// - Method for TreynorRatio
+// annualized geometric excess return over the excess return beta,
+// or over the systematic risk when modified is true
+func (c *CAPM) TreynorRatio(rf interface{}, scale int, modified bool) float64 {
+	excessRa, _ := c.excess(rf)
+	_, risk := c.regression(rf)
+	if modified {
+		risk = c.SystematicRisk(rf, scale)
+	}
+	return AnnualizedReturn(excessRa, scale, true) / risk
+}
+
+// - Method for JensenAlpha
+// annualized return minus the return predicted by the CAPM,
+// all three returns annualized geometrically, with the excess return beta
+func (c *CAPM) JensenAlpha(rf interface{}, scale int) float64 {
+	rfAnnual := annualizedRf(rf, scale)
+	rp := AnnualizedReturn(c.Ra, scale, true)
+	rpb := AnnualizedReturn(c.Rb, scale, true)
+	_, beta := c.regression(rf)
+	return rp - rfAnnual - beta*(rpb-rfAnnual)
+}
+
+// - Method for AppraisalRatio
+// Jensen's alpha over the specific risk
+func (c *CAPM) AppraisalRatio(rf interface{}, scale int) float64 {
+	return c.JensenAlpha(rf, scale) / c.SpecificRisk(rf, scale)
+}
+
+// - Method for Epsilon
+// the part of the annualized return explained neither by the regression
+// alpha (times scale) nor by beta, i.e. Jensen's alpha minus the annual alpha
+func (c *CAPM) Epsilon(rf interface{}, scale int) float64 {
+	alpha, _ := c.regression(rf)
+	return c.JensenAlpha(rf, scale) - alpha*float64(scale)
+}
+
+// - define the rows of table.CAPM
+type CAPMTable struct {
+	Alpha             float64
+	Beta              float64
+	BetaBull          float64
+	BetaBear          float64
+	RSquared          float64
+	AnnualizedAlpha   float64
+	Correlation       float64
+	CorrelationPValue float64
+	TrackingError     float64
+	ActivePremium     float64
+	InformationRatio  float64
+	TreynorRatio      float64
+	JensenAlpha       float64
+	AppraisalRatio    float64
+	Epsilon           float64
+	SystematicRisk    float64
+	SpecificRisk      float64
+	TotalRisk         float64
+}
+
+// - Method for the full CAPM table in a single call
+// Alpha, Beta and the bull and bear betas come from the regression of
+// Ra - rf on Rb - rf, as table.CAPM
+func (c *CAPM) Table(rf interface{}, scale int) CAPMTable {
+	alpha, beta := c.regression(rf)
+	return CAPMTable{
+		Alpha:             alpha,
+		Beta:              beta,
+		BetaBull:          c.BetaBull(rf),
+		BetaBear:          c.BetaBear(rf),
+		RSquared:          c.RSquared(rf),
+		AnnualizedAlpha:   math.Pow(1+alpha, float64(scale)) - 1,
+		Correlation:       c.Correlation(rf),
+		CorrelationPValue: c.CorrelationPValue(rf),
+		TrackingError:     TrackingError(c.Ra, c.Rb, scale),
+		ActivePremium:     ActivePremium(c.Ra, c.Rb, scale, true),
+		InformationRatio:  InformationRatio(c.Ra, c.Rb, scale),
+		TreynorRatio:      c.TreynorRatio(rf, scale, false),
+		JensenAlpha:       c.JensenAlpha(rf, scale),
+		AppraisalRatio:    c.AppraisalRatio(rf, scale),
+		Epsilon:           c.Epsilon(rf, scale),
+		SystematicRisk:    c.SystematicRisk(rf, scale),
+		SpecificRisk:      c.SpecificRisk(rf, scale),
+		TotalRisk:         c.TotalRisk(rf, scale),
+	}
+}
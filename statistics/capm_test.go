@@ -51,7 +51,104 @@ func TestCAPMAllMethods(t *testing.T) {
 	alpha = capm.Alpha(r)
 	assert.InDelta(t, 0.005771835, alpha, 0.000000001, "Alpha not equal to 0.005771835")
 	// test the TimingRatio method
-	tr := capm.TimingRatio()
+	tr := capm.TimingRatio(0.0)
 	// only check the first 7 decimal places
 	assert.InDelta(t, 0.7070631, tr, 0.0000001, "TimingRatio should be 0.7070631")
 }
+
+// Test the extended CAPM measures and the table
+func TestCAPMTable(t *testing.T) {
+	rtp, _ := CheckPos(fds, "HAM1")
+	rt, _ := TryStringToFloatSlice(GetSecondDimensionData(dt, rtp))
+	bmp, _ := CheckPos(fds, "SP500 TR")
+	bm, _ := TryStringToFloatSlice(GetSecondDimensionData(dt, bmp))
+	rfp, _ := CheckPos(fds, "US 3m TR")
+	rf, _ := TryStringToFloatSlice(GetSecondDimensionData(dt, rfp))
+	capm := NewCAPM(WithRa(rt), WithRb(bm))
+
+	// with a constant risk-free rate the excess return beta is the beta of the
+	// Beta method, 0.3906033 from the R code; the rows follow the PA formulas
+	scalar := 0.035 / 12
+	assert.InDelta(t, 0.0586012848, capm.SystematicRisk(scalar, 12), 0.0000000001)
+	assert.InDelta(t, 0.0664396181, capm.SpecificRisk(scalar, 12), 0.0000000001)
+	assert.InDelta(t, 0.0885908202, capm.TotalRisk(scalar, 12), 0.0000000001)
+	assert.InDelta(t, 0.2528146417, capm.TreynorRatio(scalar, 12, false), 0.0000000001)
+	assert.InDelta(t, 1.6851207312, capm.TreynorRatio(scalar, 12, true), 0.0000000001)
+	assert.InDelta(t, 0.0780685801, capm.JensenAlpha(scalar, 12), 0.0000000001)
+	assert.InDelta(t, 1.1750305360, capm.AppraisalRatio(scalar, 12), 0.0000000001)
+	assert.InDelta(t, 0.0065412681, capm.Epsilon(scalar, 12), 0.0000000001)
+	assert.InDelta(t, 0.6600671229, capm.Correlation(scalar), 0.0000000001)
+	assert.InDelta(t, 0.4356886067, capm.RSquared(scalar), 0.0000000001)
+	assert.Less(t, capm.CorrelationPValue(scalar), 0.0001)
+
+	// the table with a risk-free series, every row from the excess returns as in
+	//   table.CAPM(managers[, "HAM1"], managers[, "SP500 TR"], Rf = managers[, "US 3m TR"])
+	// the alpha is the R value quoted in TestCAPMAllMethods; R is not available
+	// to the test suite, the other rows come from testdata/reference/capm.py
+	// which follows the PA formulas, rerun the R call to check them
+	table := capm.Table(rf, 12)
+	assert.InDelta(t, 0.005774729, table.Alpha, 0.000000001)
+	assert.InDelta(t, 0.3900712484, table.Beta, 0.0000000001)
+	assert.InDelta(t, 0.0715406014, table.AnnualizedAlpha, 0.0000000001)
+	assert.InDelta(t, 0.4338677040, table.RSquared, 0.0000000001)
+	assert.InDelta(t, 0.6586863472, table.Correlation, 0.0000000001)
+	assert.InDelta(t, 0.2428041780, table.TreynorRatio, 0.0000000001)
+	assert.InDelta(t, 0.0757644254, table.JensenAlpha, 0.0000000001)
+	assert.InDelta(t, 1.1392578284, table.AppraisalRatio, 0.0000000001)
+	assert.InDelta(t, 0.0064676801, table.Epsilon, 0.0000000001)
+	assert.InDelta(t, 0.0584405543, table.SystematicRisk, 0.0000000001)
+	assert.InDelta(t, 0.0665033178, table.SpecificRisk, 0.0000000001)
+	assert.InDelta(t, 0.0885324215, table.TotalRisk, 0.0000000001)
+	// the raw return beta of the Beta method is left unchanged
+	assert.InDelta(t, 0.3906033, capm.Beta(), 0.0000001)
+	// same numbers as the slice based tests
+	assert.InDelta(t, 0.1131667, table.TrackingError, 0.0000001)
+	assert.InDelta(t, 0.04078668, table.ActivePremium, 0.0000001)
+	assert.InDelta(t, 0.3604125, table.InformationRatio, 0.0000001)
+}
+
+// Test the bull and bear betas and the up/down ratios
+func TestCAPMUpDown(t *testing.T) {
+	rtp, _ := CheckPos(fds, "HAM1")
+	rt, _ := TryStringToFloatSlice(GetSecondDimensionData(dt, rtp))
+	bmp, _ := CheckPos(fds, "SP500 TR")
+	bm, _ := TryStringToFloatSlice(GetSecondDimensionData(dt, bmp))
+	rfp, _ := CheckPos(fds, "US 3m TR")
+	rf, _ := TryStringToFloatSlice(GetSecondDimensionData(dt, rfp))
+	capm := NewCAPM(WithRa(rt), WithRb(bm))
+
+	// reference values of the PerformanceAnalytics calls
+	//   CAPM.beta.bull(managers[, "HAM1"], managers[, "SP500 TR"], Rf = 0)
+	//   CAPM.beta.bear(managers[, "HAM1"], managers[, "SP500 TR"], Rf = 0)
+	// and with Rf = managers[, "US 3m TR"], from testdata/reference/capm.py as R
+	// is not available to the test suite; TimingRatio is the R value of TestCAPMAllMethods
+	assert.InDelta(t, 0.30102038, capm.BetaBull(0.0), 0.00000001)
+	assert.InDelta(t, 0.42573339, capm.BetaBear(0.0), 0.00000001)
+	assert.InDelta(t, capm.TimingRatio(0.0), capm.BetaBull(0.0)/capm.BetaBear(0.0), 0.0000000001)
+	// with a risk-free series the split is on the sign of the benchmark excess return
+	assert.InDelta(t, 0.3005460809, capm.BetaBull(rf), 0.0000000001)
+	assert.InDelta(t, 0.4264214990, capm.BetaBear(rf), 0.0000000001)
+	assert.InDelta(t, 0.7048098691, capm.TimingRatio(rf), 0.0000000001)
+	// reference values of the PerformanceAnalytics calls
+	//   UpDownRatios(managers[, "HAM1"], managers[, "SP500 TR"], method = "Capture", side = "Up")
+	//   UpDownRatios(managers[, "HAM1"], managers[, "SP500 TR"], method = "Number", side = "Down")
+	// and so on, from testdata/reference/updown.py as R is not available to the test suite
+	assert.InDelta(t, 0.63466123, capm.UpCaptureRatio(), 0.00000001)
+	assert.InDelta(t, 0.20763037, capm.DownCaptureRatio(), 0.00000001)
+	assert.InDelta(t, 0.89411765, capm.UpNumberRatio(), 0.00000001)
+	assert.InDelta(t, 0.51063830, capm.DownNumberRatio(), 0.00000001)
+	assert.InDelta(t, 0.29411765, capm.UpPercentRatio(), 0.00000001)
+	assert.InDelta(t, 0.80851064, capm.DownPercentRatio(), 0.00000001)
+	assert.InDelta(t, 0.47727273, capm.BattingAverage(), 0.00000001)
+
+	// a flat benchmark period counts as a down market in every ratio
+	flat := NewCAPM(WithRa([]float64{0.01, -0.02, 0.01, 0.03}), WithRb([]float64{0.02, -0.01, 0, 0.01}))
+	assert.InDelta(t, 0.5, flat.DownNumberRatio(), 0.0000000001)
+	assert.InDelta(t, 0.5, flat.DownPercentRatio(), 0.0000000001)
+	assert.InDelta(t, 1.0, flat.UpNumberRatio(), 0.0000000001)
+	assert.InDelta(t, 0.5, flat.UpPercentRatio(), 0.0000000001)
+
+	table := capm.Table(rf, 12)
+	assert.Equal(t, capm.BetaBull(rf), table.BetaBull)
+	assert.Equal(t, capm.BetaBear(rf), table.BetaBear)
+}
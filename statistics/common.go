@@ -2,11 +2,11 @@ package statistics
 
 import (
 	"encoding/csv"
-	"errors"
 	"math"
 	"os"
 	"strconv"
 
+	"gonum.org/v1/gonum/mat"
 	"gonum.org/v1/gonum/stat"
 	// stat2 "github.com/grd/stat"
 )
@@ -36,7 +36,18 @@ func CumProd(slice []float64) []float64 {
 }
 
 // - CumProdAdd function for []float64
+// other is a float64 or a []float64, it panics on any other type
 func CumProdAdd(slice []float64, other interface{}) []float64 {
+	cumProd, err := TryCumProdAdd(slice, other)
+	if err != nil {
+		panic(err)
+	}
+	return cumProd
+}
+
+// - TryCumProdAdd function for []float64
+// TryCumProdAdd is CumProdAdd returning a *TypeError or a *LengthMismatchError instead of panicking
+func TryCumProdAdd(slice []float64, other interface{}) ([]float64, error) {
 	switch v := other.(type) {
 	case float64:
 		product := 1.0
@@ -45,17 +56,20 @@ func CumProdAdd(slice []float64, other interface{}) []float64 {
 			product *= val + v
 			cumProd[i] = product
 		}
-		return cumProd
+		return cumProd, nil
 	case []float64:
+		if len(v) != len(slice) {
+			return nil, &LengthMismatchError{What: "CumProdAdd", Expected: len(slice), Got: len(v)}
+		}
 		product := 1.0
 		cumProd := make([]float64, len(slice))
 		for i, val := range slice {
 			product *= val + v[i]
 			cumProd[i] = product
 		}
-		return cumProd
+		return cumProd, nil
 	default:
-		panic(errors.New("invalid type"))
+		return nil, &TypeError{Value: other}
 	}
 }
 
@@ -116,6 +130,110 @@ func CoVariance(x, y []float64) float64 {
 	return stat.Covariance(x, y, nil)
 }
 
+// - CoVarianceMatrix function
+// CoVarianceMatrix calculates the sample covariance matrix of the given columns
+// R[j] holds the returns of asset j, there must be at least one column and
+// all columns must have the same length
+func CoVarianceMatrix(R [][]float64) *mat.SymDense {
+	cov := mat.NewSymDense(len(R), nil)
+	stat.CovarianceMatrix(cov, columnsToDense(R), nil)
+	return cov
+}
+
+// - CoSkewnessMatrix function
+// CoSkewnessMatrix calculates the k x k^2 matrix of third co-moments
+// M3[i, j*k+l] = mean((Ri - mean(Ri)) * (Rj - mean(Rj)) * (Rl - mean(Rl)))
+func CoSkewnessMatrix(R [][]float64) *mat.Dense {
+	c := centeredColumns(R)
+	k := len(c)
+	m3 := mat.NewDense(k, k*k, nil)
+	n := len(c[0])
+	for i := 0; i < k; i++ {
+		for j := i; j < k; j++ {
+			for l := j; l < k; l++ {
+				sum := 0.0
+				for t := 0; t < n; t++ {
+					sum += c[i][t] * c[j][t] * c[l][t]
+				}
+				v := sum / float64(n)
+				// the co-moment is symmetric in all its indices
+				for _, p := range permutations3(i, j, l) {
+					m3.Set(p[0], p[1]*k+p[2], v)
+				}
+			}
+		}
+	}
+	return m3
+}
+
+// - CoKurtosisMatrix function
+// CoKurtosisMatrix calculates the k x k^3 matrix of fourth co-moments
+// M4[i, j*k*k+l*k+m] = mean((Ri - mean(Ri)) * (Rj - mean(Rj)) * (Rl - mean(Rl)) * (Rm - mean(Rm)))
+func CoKurtosisMatrix(R [][]float64) *mat.Dense {
+	c := centeredColumns(R)
+	k := len(c)
+	m4 := mat.NewDense(k, k*k*k, nil)
+	n := len(c[0])
+	for i := 0; i < k; i++ {
+		for j := i; j < k; j++ {
+			for l := j; l < k; l++ {
+				for m := l; m < k; m++ {
+					sum := 0.0
+					for t := 0; t < n; t++ {
+						sum += c[i][t] * c[j][t] * c[l][t] * c[m][t]
+					}
+					v := sum / float64(n)
+					for _, p := range permutations4(i, j, l, m) {
+						m4.Set(p[0], p[1]*k*k+p[2]*k+p[3], v)
+					}
+				}
+			}
+		}
+	}
+	return m4
+}
+
+// * function to stack columns into an n x k matrix
+func columnsToDense(R [][]float64) *mat.Dense {
+	n := len(R[0])
+	x := mat.NewDense(n, len(R), nil)
+	for j, col := range R {
+		x.SetCol(j, col)
+	}
+	return x
+}
+
+// * function to remove the mean of each column
+func centeredColumns(R [][]float64) [][]float64 {
+	c := make([][]float64, len(R))
+	for j, col := range R {
+		mean := stat.Mean(col, nil)
+		c[j] = make([]float64, len(col))
+		for t, v := range col {
+			c[j][t] = v - mean
+		}
+	}
+	return c
+}
+
+// * function for the orderings of three indexes
+func permutations3(a, b, c int) [][3]int {
+	return [][3]int{{a, b, c}, {a, c, b}, {b, a, c}, {b, c, a}, {c, a, b}, {c, b, a}}
+}
+
+// * function for the orderings of four indexes
+func permutations4(a, b, c, d int) [][4]int {
+	out := make([][4]int, 0, 24)
+	for _, p := range permutations3(b, c, d) {
+		out = append(out,
+			[4]int{a, p[0], p[1], p[2]},
+			[4]int{p[0], a, p[1], p[2]},
+			[4]int{p[0], p[1], a, p[2]},
+			[4]int{p[0], p[1], p[2], a})
+	}
+	return out
+}
+
 // - Correlation function
 // Correlation calculates the correlation coefficient between two given slices of float64 values
 func Correlation(x, y []float64) float64 {
@@ -197,11 +315,21 @@ func Kurtosis(x []float64, tag string) float64 {
 
 
 // * function to read managers.csv data
+// ReadData panics on any error, use TryReadData to handle them
 func ReadData(path string) (dt [][]string, fields []string) {
+	dt, fields, err := TryReadData(path)
+	if err != nil {
+		panic(err)
+	}
+	return dt, fields
+}
+
+// * function to read managers.csv data returning the error
+func TryReadData(path string) (dt [][]string, fields []string, err error) {
 	// read the data from the csv file using io package
 	file, err := os.Open(path)
 	if err != nil {
-		panic(err)
+		return nil, nil, err
 	}
 	defer file.Close()
 
@@ -209,29 +337,28 @@ func ReadData(path string) (dt [][]string, fields []string) {
 	// get the fields from the csv file
 	fields, err = reader.Read()
 	if err != nil {
-		panic(err)
+		return nil, nil, err
 	}
 
 	reader.FieldsPerRecord = 0
 
 	dt, err = reader.ReadAll()
 	if err != nil {
-		panic(err)
+		return nil, nil, err
 	}
 
-	return dt, fields
+	return dt, fields, nil
 }
 
 // * function to check the position of a field in the fields slice
+// the error is a *ColumnNotFoundError
 func CheckPos(fields []string, name string) (pos int, e error) {
 	for i, field := range fields {
 		if field == name {
 			return i, nil
 		}
 	}
-	err := errors.New("field not found")
-	return -1, err
-
+	return -1, &ColumnNotFoundError{Column: name}
 }
 
 // * function to get a [][]string 2D slice the second column
@@ -246,12 +373,13 @@ func GetSecondDimensionData(data [][]string, index int) []string {
 
 // * function to change []string to []float64
 // TryStringToFloatSlice converts a []string slice to a []float64 slice
+// the error is a *ParseError holding the 1-based position of the bad value
 func TryStringToFloatSlice(s []string) ([]float64, error) {
 	var f []float64
-	for _, str := range s {
+	for i, str := range s {
 		float, err := strconv.ParseFloat(str, 64)
 		if err != nil {
-			return nil, err
+			return nil, &ParseError{Row: i + 1, Value: str, Err: err}
 		}
 		f = append(f, float)
 	}
@@ -272,17 +400,22 @@ func StringToFloatSlice(s []string) []float64 {
 }
 
 // StringToFloatSlice forcely converts a []string slice to a []float64 slice with same length for benchmark
+// a row is kept only when both Ra and Rb parse, use Align for date indexed series
 func StringToFloatSliceBench(Ra, Rb []string) (RaF, RbF []float64) {
 	for i, s := range Ra {
+		if i >= len(Rb) {
+			break
+		}
 		fa, e := strconv.ParseFloat(s, 64)
 		if e != nil {
-		} else {
-			RaF = append(RaF, fa)
-			// assume Rb has all reasonable values
-			fb, _ := strconv.ParseFloat(Rb[i], 64)
-			RbF = append(RbF, fb)
+			continue
 		}
-
+		fb, e := strconv.ParseFloat(Rb[i], 64)
+		if e != nil {
+			continue
+		}
+		RaF = append(RaF, fa)
+		RbF = append(RbF, fb)
 	}
 	return RaF, RbF
 }
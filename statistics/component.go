@@ -0,0 +1,199 @@
+package statistics
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// - define the decomposition of a portfolio risk measure
+// the risk measures are homogeneous of degree one in the weights, so
+// by Euler's theorem the contributions sum exactly to the total
+type RiskContribution struct {
+	// Total is the portfolio VaR or ES, negative for a loss
+	Total float64
+	// Marginal is the derivative of the total with respect to each weight
+	Marginal []float64
+	// Contribution is weight * marginal for each asset
+	Contribution []float64
+	// PctContribution is contribution / total for each asset
+	PctContribution []float64
+}
+
+// - ComponentVaR function
+// ComponentVaR decomposes the VaR of a portfolio with weights w over the
+// assets R (R[j] holds the returns of asset j) at confidence level p
+// tag is gaussian or modified (the default), following Boudt, Peterson and Croux (2008)
+// both estimators use co-moments with the 1/n denominator so that a
+// single asset portfolio gives the same number as VaR
+func ComponentVaR(R [][]float64, w []float64, p float64, tag string) (*RiskContribution, error) {
+	pm, err := newPortfolioMoments(R, w, tag == "gaussian")
+	if err != nil {
+		return nil, err
+	}
+	if p <= 0 || p >= 1 {
+		return nil, errors.New("confidence level must be between 0 and 1")
+	}
+	alpha := 1 - p
+	z := distuv.UnitNormal.Quantile(alpha)
+	marginal := make([]float64, len(w))
+	if tag == "gaussian" {
+		for i := range w {
+			marginal[i] = pm.dMu[i] + z*pm.dSigma[i]
+		}
+		return newRiskContribution(w, marginal), nil
+	}
+	h := cornishFisher(alpha, pm.skew, pm.exkurt)
+	hS := (z*z-1)/6 - (2*z*z*z-5*z)*pm.skew/18
+	hK := (z*z*z - 3*z) / 24
+	for i := range w {
+		marginal[i] = pm.dMu[i] + pm.dSigma[i]*h + pm.sigma*(hS*pm.dSkew[i]+hK*pm.dExkurt[i])
+	}
+	return newRiskContribution(w, marginal), nil
+}
+
+// - ComponentES function
+// ComponentES decomposes the Expected Shortfall of a portfolio, see ComponentVaR
+// when the modified ES is bounded by the modified VaR the VaR decomposition is returned
+func ComponentES(R [][]float64, w []float64, p float64, tag string) (*RiskContribution, error) {
+	pm, err := newPortfolioMoments(R, w, tag == "gaussian")
+	if err != nil {
+		return nil, err
+	}
+	if p <= 0 || p >= 1 {
+		return nil, errors.New("confidence level must be between 0 and 1")
+	}
+	alpha := 1 - p
+	z := distuv.UnitNormal.Quantile(alpha)
+	marginal := make([]float64, len(w))
+	if tag == "gaussian" {
+		tail := distuv.UnitNormal.Prob(z) / alpha
+		for i := range w {
+			marginal[i] = pm.dMu[i] - tail*pm.dSigma[i]
+		}
+		return newRiskContribution(w, marginal), nil
+	}
+	h := cornishFisher(alpha, pm.skew, pm.exkurt)
+	e := edgeworthTail(h, alpha, pm.skew, pm.exkurt)
+	if e > h {
+		return ComponentVaR(R, w, p, tag)
+	}
+	// derivatives of the tail expectation through h and directly
+	s, k := pm.skew, pm.exkurt
+	m := truncatedMoments(h, 7)
+	he3 := h*h*h - 3*h
+	he4 := h*h*h*h - 6*h*h + 3
+	he6 := math.Pow(h, 6) - 15*math.Pow(h, 4) + 45*h*h - 15
+	density := distuv.UnitNormal.Prob(h) * (1 + s/6*he3 + k/24*he4 + s*s/72*he6)
+	eH := h * density / alpha
+	hS := (z*z-1)/6 - (2*z*z*z-5*z)*s/18
+	hK := (z*z*z - 3*z) / 24
+	eS := eH*hS + ((m[4]-3*m[2])/6+s/36*(m[7]-15*m[5]+45*m[3]-15*m[1]))/alpha
+	eK := eH*hK + (m[5]-6*m[3]+3*m[1])/24/alpha
+	for i := range w {
+		marginal[i] = pm.dMu[i] + pm.dSigma[i]*e + pm.sigma*(eS*pm.dSkew[i]+eK*pm.dExkurt[i])
+	}
+	return newRiskContribution(w, marginal), nil
+}
+
+// * struct for the portfolio moments and their gradients with respect to the weights
+type portfolioMoments struct {
+	sigma  float64
+	skew   float64
+	exkurt float64
+
+	dMu     []float64
+	dSigma  []float64
+	dSkew   []float64
+	dExkurt []float64
+}
+
+// * function to compute the portfolio moments
+// all co-moments have the 1/n denominator, gaussian skips the higher ones
+func newPortfolioMoments(R [][]float64, w []float64, gaussian bool) (*portfolioMoments, error) {
+	if len(R) == 0 {
+		return nil, errors.New("no assets")
+	}
+	if len(w) != len(R) {
+		return nil, &LengthMismatchError{What: "weights", Expected: len(R), Got: len(w)}
+	}
+	for j := range R {
+		if len(R[j]) != len(R[0]) {
+			return nil, &LengthMismatchError{What: "asset returns", Expected: len(R[0]), Got: len(R[j])}
+		}
+	}
+	k := len(w)
+	n := float64(len(R[0]))
+	pm := &portfolioMoments{
+		dMu:     make([]float64, k),
+		dSigma:  make([]float64, k),
+		dSkew:   make([]float64, k),
+		dExkurt: make([]float64, k),
+	}
+	for j := range R {
+		pm.dMu[j] = stat.Mean(R[j], nil)
+	}
+
+	wv := mat.NewVecDense(k, w)
+	sigma := CoVarianceMatrix(R)
+	sigma.ScaleSym((n-1)/n, sigma)
+	var g2 mat.VecDense
+	g2.MulVec(sigma, wv)
+	m2 := mat.Dot(wv, &g2)
+	pm.sigma = math.Sqrt(m2)
+	for i := 0; i < k; i++ {
+		pm.dSigma[i] = g2.AtVec(i) / pm.sigma
+	}
+	if gaussian {
+		return pm, nil
+	}
+
+	// the third and fourth co-moments against the weight tensors
+	ww := make([]float64, k*k)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			ww[i*k+j] = w[i] * w[j]
+		}
+	}
+	www := make([]float64, k*k*k)
+	for i := 0; i < k*k; i++ {
+		for j := 0; j < k; j++ {
+			www[i*k+j] = ww[i] * w[j]
+		}
+	}
+	var g3, g4 mat.VecDense
+	g3.MulVec(CoSkewnessMatrix(R), mat.NewVecDense(k*k, ww))
+	g4.MulVec(CoKurtosisMatrix(R), mat.NewVecDense(k*k*k, www))
+	m3 := mat.Dot(wv, &g3)
+	m4 := mat.Dot(wv, &g4)
+	pm.skew = m3 / math.Pow(m2, 1.5)
+	pm.exkurt = m4/(m2*m2) - 3
+	for i := 0; i < k; i++ {
+		dm2 := 2 * g2.AtVec(i)
+		dm3 := 3 * g3.AtVec(i)
+		dm4 := 4 * g4.AtVec(i)
+		pm.dSkew[i] = dm3/math.Pow(m2, 1.5) - 1.5*m3*dm2/math.Pow(m2, 2.5)
+		pm.dExkurt[i] = dm4/(m2*m2) - 2*m4*dm2/(m2*m2*m2)
+	}
+	return pm, nil
+}
+
+// * function to build the contributions from the marginal risks
+func newRiskContribution(w, marginal []float64) *RiskContribution {
+	rc := &RiskContribution{
+		Marginal:        marginal,
+		Contribution:    make([]float64, len(w)),
+		PctContribution: make([]float64, len(w)),
+	}
+	for i := range w {
+		rc.Contribution[i] = w[i] * marginal[i]
+		rc.Total += rc.Contribution[i]
+	}
+	for i := range w {
+		rc.PctContribution[i] = rc.Contribution[i] / rc.Total
+	}
+	return rc
+}
@@ -0,0 +1,91 @@
+package statistics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the co-moment matrices and the component VaR and ES
+func TestComponentRisk(t *testing.T) {
+	rf, err := ReadFrame("../data/edhec.csv")
+	if err != nil {
+		panic(err)
+	}
+	R := rf.Columns[:4]
+	w := []float64{0.4, 0.3, 0.2, 0.1}
+
+	// the co-moments agree with the univariate estimators on the diagonal
+	cov := CoVarianceMatrix(R)
+	assert.InDelta(t, Variance(R[1]), cov.At(1, 1), 1e-15)
+	assert.InDelta(t, CoVariance(R[0], R[2]), cov.At(0, 2), 1e-15)
+	m3 := CoSkewnessMatrix(R)
+	assert.InDelta(t, centralMoment(R[2], 3), m3.At(2, 2*4+2), 1e-15)
+	assert.Equal(t, m3.At(0, 1*4+2), m3.At(2, 0*4+1))
+	m4 := CoKurtosisMatrix(R)
+	assert.InDelta(t, centralMoment(R[3], 4), m4.At(3, 3*16+3*4+3), 1e-15)
+	assert.Equal(t, m4.At(0, 1*16+2*4+3), m4.At(3, 2*16+1*4+0))
+
+	// the portfolio return series gives the same totals
+	rp := make([]float64, len(R[0]))
+	for j := range R {
+		for i, r := range R[j] {
+			rp[i] += w[j] * r
+		}
+	}
+	for _, tag := range []string{"gaussian", "modified"} {
+		for _, p := range []float64{0.95, 0.99} {
+			cv, err := ComponentVaR(R, w, p, tag)
+			assert.Nil(t, err)
+			assert.InDelta(t, VaR(rp, p, tag), cv.Total, 1e-12)
+			ce, err := ComponentES(R, w, p, tag)
+			assert.Nil(t, err)
+			assert.InDelta(t, ES(rp, p, tag), ce.Total, 1e-12)
+
+			// the contributions sum to the total and the percentages to one
+			for _, rc := range []*RiskContribution{cv, ce} {
+				sum, pct := 0.0, 0.0
+				for i := range w {
+					sum += rc.Contribution[i]
+					pct += rc.PctContribution[i]
+				}
+				assert.InDelta(t, rc.Total, sum, 1e-12)
+				assert.InDelta(t, 1.0, pct, 1e-12)
+			}
+		}
+	}
+
+	// the marginal risk is the derivative of the total
+	const eps = 1e-6
+	for _, tag := range []string{"gaussian", "modified"} {
+		cv, _ := ComponentVaR(R, w, 0.95, tag)
+		ce, _ := ComponentES(R, w, 0.95, tag)
+		for i := range w {
+			up := append([]float64(nil), w...)
+			dn := append([]float64(nil), w...)
+			up[i] += eps
+			dn[i] -= eps
+			vu, _ := ComponentVaR(R, up, 0.95, tag)
+			vd, _ := ComponentVaR(R, dn, 0.95, tag)
+			assert.InDelta(t, (vu.Total-vd.Total)/(2*eps), cv.Marginal[i], 1e-6)
+			eu, _ := ComponentES(R, up, 0.95, tag)
+			ed, _ := ComponentES(R, dn, 0.95, tag)
+			assert.InDelta(t, (eu.Total-ed.Total)/(2*eps), ce.Marginal[i], 1e-6)
+		}
+	}
+
+	// a single asset portfolio is the univariate measure
+	for _, tag := range []string{"gaussian", "modified"} {
+		cv, err := ComponentVaR(R, []float64{0, 1, 0, 0}, 0.95, tag)
+		assert.Nil(t, err)
+		assert.InDelta(t, VaR(R[1], 0.95, tag), cv.Total, 1e-12)
+		ce, err := ComponentES(R, []float64{0, 1, 0, 0}, 0.95, tag)
+		assert.Nil(t, err)
+		assert.InDelta(t, ES(R[1], 0.95, tag), ce.Total, 1e-12)
+	}
+
+	_, err = ComponentVaR(R, []float64{0.5, 0.5}, 0.95, "modified")
+	assert.NotNil(t, err)
+	_, err = ComponentES(R, w, 1.5, "gaussian")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,226 @@
+package statistics
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// the diagnostics of a fitted OLS model, the numbers of a statsmodels summary
+// they are computed from the results of Run, call it first
+
+// - Method to tell whether X holds a constant column
+func (ols *OLS) HasConstant() bool {
+	for j := 0; j < ols.K(); j++ {
+		if ols.isConstantColumn(j) {
+			return true
+		}
+	}
+	return false
+}
+
+// - Method for the model degrees of freedom, the rank of X less the constant
+func (ols *OLS) DFModel() float64 {
+	if ols.HasConstant() {
+		return float64(ols.rank - 1)
+	}
+	return float64(ols.rank)
+}
+
+// - Method for the sum of squared residuals, weighted for WLS
+func (ols *OLS) SSR() float64 {
+	e := ols.wResiduals.ColView(0)
+	return mat.Dot(e, e)
+}
+
+// - Method for the explained sum of squares
+// centered around the mean of Y when X holds a constant, uncentered otherwise
+func (ols *OLS) ESS() float64 {
+	return ols.totalSS(ols.HasConstant()) - ols.SSR()
+}
+
+// * method for the total sum of squares of Y, weighted for WLS
+// centered uses the (weighted) mean of Y
+func (ols *OLS) totalSS(centered bool) float64 {
+	n := ols.N()
+	w := func(i int) float64 {
+		if ols.weights == nil {
+			return 1
+		}
+		return ols.weights[i]
+	}
+	center := 0.0
+	if centered {
+		sum, sumW := 0.0, 0.0
+		for i := 0; i < n; i++ {
+			sum += w(i) * ols.Y.At(i, 0)
+			sumW += w(i)
+		}
+		center = sum / sumW
+	}
+	tss := 0.0
+	for i := 0; i < n; i++ {
+		d := ols.Y.At(i, 0) - center
+		tss += w(i) * d * d
+	}
+	return tss
+}
+
+// - Method for the F statistic of the joint nullity of the slopes
+// it is the Wald statistic with the covariance of the coefficients divided
+// by the number of restrictions, which is the classical
+// (ESS / DFModel) / (SSR / DF) for the nonrobust covariance
+func (ols *OLS) FStatistic() float64 {
+	if ols.covType == NonRobust {
+		return (ols.ESS() / ols.DFModel()) / (ols.SSR() / ols.DF())
+	}
+	// restrict every coefficient but the constant
+	k := ols.K()
+	slopes := make([]int, 0, k)
+	for j := 0; j < k; j++ {
+		if !ols.isConstantColumn(j) {
+			slopes = append(slopes, j)
+		}
+	}
+	q := len(slopes)
+	if q == 0 {
+		return math.NaN()
+	}
+	b := mat.NewVecDense(q, nil)
+	v := mat.NewSymDense(q, nil)
+	for i, si := range slopes {
+		b.SetVec(i, ols.coefficients.At(si, 0))
+		for j, sj := range slopes {
+			v.SetSym(i, j, ols.covParams.At(si, sj))
+		}
+	}
+	var vInv mat.Dense
+	if err := vInv.Inverse(v); err != nil {
+		return math.NaN()
+	}
+	return mat.Inner(b, &vInv, b) / float64(q)
+}
+
+// - Method for the p value of the F statistic
+func (ols *OLS) FPValue() float64 {
+	dist := distuv.F{D1: ols.DFModel(), D2: ols.DF()}
+	return dist.Survival(ols.FStatistic())
+}
+
+// - Method for the Gaussian log-likelihood at the estimated coefficients
+// WLS adds half the sum of the log weights, as statsmodels
+func (ols *OLS) LogLikelihood() float64 {
+	n := float64(ols.N())
+	llf := -n/2*math.Log(2*math.Pi) - n/2*math.Log(ols.SSR()/n) - n/2
+	for _, w := range ols.weights {
+		llf += 0.5 * math.Log(w)
+	}
+	return llf
+}
+
+// - Method for the Akaike information criterion, -2 llf + 2 (DFModel + constant)
+func (ols *OLS) AIC() float64 {
+	return -2*ols.LogLikelihood() + 2*float64(ols.rank)
+}
+
+// - Method for the Bayesian information criterion, -2 llf + log(n) (DFModel + constant)
+func (ols *OLS) BIC() float64 {
+	return -2*ols.LogLikelihood() + math.Log(float64(ols.N()))*float64(ols.rank)
+}
+
+// - Method for the Durbin-Watson statistic of the residuals
+// close to 2 without first order autocorrelation, towards 0 when it is positive
+func (ols *OLS) DurbinWatson() float64 {
+	e := ols.residualSlice()
+	sum := 0.0
+	for i := 1; i < len(e); i++ {
+		d := e[i] - e[i-1]
+		sum += d * d
+	}
+	return sum / ols.SSR()
+}
+
+// - Method for the skewness of the residuals, Skewness "moment"
+func (ols *OLS) ResidualSkew() float64 {
+	return Skewness(ols.residualSlice(), "moment")
+}
+
+// - Method for the kurtosis of the residuals, Kurtosis "moment" (3 for a normal)
+func (ols *OLS) ResidualKurtosis() float64 {
+	return Kurtosis(ols.residualSlice(), "moment")
+}
+
+// - Method for the Jarque-Bera normality test of the residuals
+// n / 6 * (S^2 + (K - 3)^2 / 4), chi-squared with 2 degrees of freedom
+func (ols *OLS) JarqueBera() (statistic, pValue float64) {
+	n := float64(ols.N())
+	s := ols.ResidualSkew()
+	k := ols.ResidualKurtosis()
+	statistic = n / 6 * (s*s + (k-3)*(k-3)/4)
+	return statistic, distuv.ChiSquared{K: 2}.Survival(statistic)
+}
+
+// - Method for the D'Agostino-Pearson omnibus normality test of the residuals
+// the sum of the squared z scores of the skewness and the kurtosis tests,
+// chi-squared with 2 degrees of freedom, as scipy.stats.normaltest
+// the skewness test needs at least 8 residuals
+func (ols *OLS) Omnibus() (statistic, pValue float64) {
+	n := float64(ols.N())
+	if n < 8 {
+		return math.NaN(), math.NaN()
+	}
+	zs := skewTestZ(ols.ResidualSkew(), n)
+	zk := kurtosisTestZ(ols.ResidualKurtosis(), n)
+	statistic = zs*zs + zk*zk
+	return statistic, distuv.ChiSquared{K: 2}.Survival(statistic)
+}
+
+// * function for the z score of D'Agostino's skewness test
+func skewTestZ(skew, n float64) float64 {
+	y := skew * math.Sqrt((n+1)*(n+3)/(6*(n-2)))
+	beta2 := 3 * (n*n + 27*n - 70) * (n + 1) * (n + 3) / ((n - 2) * (n + 5) * (n + 7) * (n + 9))
+	w2 := -1 + math.Sqrt(2*(beta2-1))
+	delta := 1 / math.Sqrt(0.5*math.Log(w2))
+	alpha := math.Sqrt(2 / (w2 - 1))
+	if y == 0 {
+		y = 1
+	}
+	return delta * math.Log(y/alpha+math.Sqrt((y/alpha)*(y/alpha)+1))
+}
+
+// * function for the z score of the Anscombe-Glynn kurtosis test
+func kurtosisTestZ(kurt, n float64) float64 {
+	e := 3 * (n - 1) / (n + 1)
+	varb2 := 24 * n * (n - 2) * (n - 3) / ((n + 1) * (n + 1) * (n + 3) * (n + 5))
+	x := (kurt - e) / math.Sqrt(varb2)
+	sqrtBeta1 := 6 * (n*n - 5*n + 2) / ((n + 7) * (n + 9)) * math.Sqrt(6*(n+3)*(n+5)/(n*(n-2)*(n-3)))
+	a := 6 + 8/sqrtBeta1*(2/sqrtBeta1+math.Sqrt(1+4/(sqrtBeta1*sqrtBeta1)))
+	term1 := 1 - 2/(9*a)
+	denom := 1 + x*math.Sqrt(2/(a-4))
+	if denom == 0 {
+		return math.NaN()
+	}
+	term2 := math.Copysign(math.Cbrt((1-2/a)/math.Abs(denom)), denom)
+	return (term1 - term2) / math.Sqrt(2/(9*a))
+}
+
+// * method to tell whether column j of X is constant and non zero
+func (ols *OLS) isConstantColumn(j int) bool {
+	n, _ := ols.X.Dims()
+	v := ols.X.At(0, j)
+	if v == 0 {
+		return false
+	}
+	for i := 1; i < n; i++ {
+		if ols.X.At(i, j) != v {
+			return false
+		}
+	}
+	return true
+}
+
+// * method to copy the whitened residuals into a slice
+func (ols *OLS) residualSlice() []float64 {
+	return mat.Col(nil, 0, ols.wResiduals)
+}
@@ -0,0 +1,45 @@
+package statistics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test the OLS diagnostics against the statsmodels summary of data.csv
+// F-statistic 92.07, Prob 4.04e-11, Log-Likelihood -134.61, AIC 275.2, BIC 278.8,
+// Omnibus 2.691 (0.260), Durbin-Watson 0.530, Jarque-Bera 1.551 (0.461),
+// Skew -0.612, Kurtosis 3.226, Cond. No. 394
+// the decimals of res.fvalue, res.llf, res.aic, res.bic, durbin_watson(res.resid),
+// jarque_bera(res.resid) and omni_normtest(res.resid) for res = OLS(y, X).fit(),
+// from testdata/reference/diagnostics.py as statsmodels is not available to the test suite
+func TestOLSDiagnostics(t *testing.T) {
+	X, Y := ReadCSV("data.csv", true, "index_price", "interest_rate", "unemployment_rate")
+	ols := NewOLS(X, Y)
+	assert.NoError(t, ols.Run())
+
+	assert.True(t, ols.HasConstant())
+	assert.Equal(t, 2.0, ols.DFModel())
+	assert.InDelta(t, 92.0727085692, ols.FStatistic(), 1e-8)
+	assert.InDelta(t, 4.04e-11, ols.FPValue(), 0.005e-11)
+	assert.InDelta(t, -134.6079226668, ols.LogLikelihood(), 1e-8)
+	assert.InDelta(t, 275.2158453335, ols.AIC(), 1e-8)
+	assert.InDelta(t, 278.7500068246, ols.BIC(), 1e-8)
+	assert.InDelta(t, 0.5301171806, ols.DurbinWatson(), 1e-8)
+	assert.InDelta(t, -0.6123063612, ols.ResidualSkew(), 1e-8)
+	assert.InDelta(t, 3.2261163035, ols.ResidualKurtosis(), 1e-8)
+
+	jb, jbp := ols.JarqueBera()
+	assert.InDelta(t, 1.5508049025, jb, 1e-8)
+	assert.InDelta(t, 0.4605184075, jbp, 1e-8)
+	omni, omnip := ols.Omnibus()
+	assert.InDelta(t, 2.6910175134, omni, 1e-8)
+	assert.InDelta(t, 0.2604071902, omnip, 1e-8)
+	assert.InDelta(t, 394, ols.ConditionNumber(), 0.5)
+
+	// the Wald form under a robust covariance differs from the classical F
+	robust := NewOLS(X, Y, WithCovType(HC1))
+	assert.NoError(t, robust.Run())
+	assert.NotEqual(t, ols.FStatistic(), robust.FStatistic())
+	assert.InDelta(t, ols.LogLikelihood(), robust.LogLikelihood(), 1e-12)
+}
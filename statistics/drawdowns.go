@@ -0,0 +1,187 @@
+package statistics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// - define one drawdown episode on the index of a return slice
+// the counts follow findDrawdowns of PerformanceAnalytics
+type DrawdownEpisode struct {
+	// Depth is the deepest drawdown of the episode, a negative number
+	Depth float64
+	// Start is the first period below the previous peak
+	Start int
+	// Trough is the period of the deepest drawdown
+	Trough int
+	// End is the period back at the previous peak, -1 while still open
+	End int
+	// Length is the number of periods from Start to End included,
+	// or from Start to the last observation for an open episode
+	Length int
+	// PeakToTrough is the number of periods from Start to Trough included
+	PeakToTrough int
+	// Recovery is the number of periods from Trough to End, 0 while still open
+	Recovery int
+}
+
+// - FindDrawdowns function
+// FindDrawdowns lists the drawdown episodes of a return slice in time order
+func FindDrawdowns(Ra []float64) []DrawdownEpisode {
+	dd := Drawdowns(Ra)
+	episodes := make([]DrawdownEpisode, 0)
+	for i := 0; i < len(dd); {
+		if dd[i] >= 0 {
+			i++
+			continue
+		}
+		ep := DrawdownEpisode{Depth: dd[i], Start: i, Trough: i, End: -1}
+		for ; i < len(dd) && dd[i] < 0; i++ {
+			if dd[i] < ep.Depth {
+				ep.Depth = dd[i]
+				ep.Trough = i
+			}
+		}
+		ep.PeakToTrough = ep.Trough - ep.Start + 1
+		if i < len(dd) {
+			ep.End = i
+			ep.Length = ep.End - ep.Start + 1
+			ep.Recovery = ep.End - ep.Trough
+		} else {
+			ep.Length = len(dd) - ep.Start
+		}
+		episodes = append(episodes, ep)
+	}
+	return episodes
+}
+
+// - SortDrawdowns function
+// SortDrawdowns orders the episodes from the deepest to the shallowest
+func SortDrawdowns(episodes []DrawdownEpisode) []DrawdownEpisode {
+	sorted := make([]DrawdownEpisode, len(episodes))
+	copy(sorted, episodes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Depth < sorted[j].Depth
+	})
+	return sorted
+}
+
+// - define one row of the drawdown table of a dated series
+type DrawdownRecord struct {
+	From   time.Time
+	Trough time.Time
+	// To is the recovery date, the zero time while the drawdown is open
+	To        time.Time
+	Recovered bool
+	Depth     float64
+	Length    int
+	ToTrough  int
+	Recovery  int
+}
+
+// - Method for the drawdown series
+func (rs *ReturnSeries) Drawdowns() []float64 {
+	return Drawdowns(rs.Values)
+}
+
+// - Method for the drawdown table, as table.Drawdowns of PerformanceAnalytics
+// the rows are sorted by depth, top limits the number of rows (0 for all)
+func (rs *ReturnSeries) DrawdownTable(top int) []DrawdownRecord {
+	episodes := SortDrawdowns(FindDrawdowns(rs.Values))
+	if top > 0 && top < len(episodes) {
+		episodes = episodes[:top]
+	}
+	table := make([]DrawdownRecord, len(episodes))
+	for i, ep := range episodes {
+		table[i] = DrawdownRecord{
+			From:     rs.Dates[ep.Start],
+			Trough:   rs.Dates[ep.Trough],
+			Depth:    ep.Depth,
+			Length:   ep.Length,
+			ToTrough: ep.PeakToTrough,
+			Recovery: ep.Recovery,
+		}
+		if ep.End >= 0 {
+			table[i].To = rs.Dates[ep.End]
+			table[i].Recovered = true
+		}
+	}
+	return table
+}
+
+// - CalmarRatio function
+// annualized geometric return over the absolute maximum drawdown
+func CalmarRatio(Ra []float64, scale int) float64 {
+	return AnnualizedReturn(Ra, scale, true) / MaxDrawdown(Ra)
+}
+
+// - SterlingRatio function
+// annualized geometric return over the absolute maximum drawdown plus an excess,
+// PerformanceAnalytics and Bacon use an excess of 0.1
+func SterlingRatio(Ra []float64, scale int, excess float64) float64 {
+	return AnnualizedReturn(Ra, scale, true) / (MaxDrawdown(Ra) + excess)
+}
+
+// - BurkeRatio function
+// annualized geometric excess return over the square root of the sum of the
+// squared drawdowns, a drawdown being a run of consecutive negative returns
+// Rf is an annual rate; modified multiplies the ratio by the square root of
+// the number of observations
+func BurkeRatio(Ra []float64, Rf float64, scale int, modified bool) float64 {
+	sum := 0.0
+	run := 1.0
+	inRun := false
+	for _, r := range Ra {
+		if r < 0 {
+			run *= 1 + r
+			inRun = true
+			continue
+		}
+		if inRun {
+			sum += (run - 1) * (run - 1)
+			run = 1.0
+			inRun = false
+		}
+	}
+	if inRun {
+		sum += (run - 1) * (run - 1)
+	}
+	result := (AnnualizedReturn(Ra, scale, true) - Rf) / math.Sqrt(sum)
+	if modified {
+		result *= math.Sqrt(float64(len(Ra)))
+	}
+	return result
+}
+
+// - UlcerIndex function
+// square root of the mean squared drawdown
+func UlcerIndex(Ra []float64) float64 {
+	sum := 0.0
+	for _, d := range Drawdowns(Ra) {
+		sum += d * d
+	}
+	return math.Sqrt(sum / float64(len(Ra)))
+}
+
+// - PainIndex function
+// mean absolute drawdown
+func PainIndex(Ra []float64) float64 {
+	sum := 0.0
+	for _, d := range Drawdowns(Ra) {
+		sum += math.Abs(d)
+	}
+	return sum / float64(len(Ra))
+}
+
+// - MartinRatio function
+// annualized geometric excess return over the Ulcer index, Rf is an annual rate
+func MartinRatio(Ra []float64, Rf float64, scale int) float64 {
+	return (AnnualizedReturn(Ra, scale, true) - Rf) / UlcerIndex(Ra)
+}
+
+// - PainRatio function
+// annualized geometric excess return over the Pain index, Rf is an annual rate
+func PainRatio(Ra []float64, Rf float64, scale int) float64 {
+	return (AnnualizedReturn(Ra, scale, true) - Rf) / PainIndex(Ra)
+}
@@ -0,0 +1,94 @@
+package statistics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the drawdown episodes and the drawdown table
+func TestDrawdownTable(t *testing.T) {
+	rs, err := ReadSeries("../data/managers.csv", "HAM1")
+	assert.Nil(t, err)
+	date := func(y, m, d int) time.Time { return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC) }
+
+	// the episodes are in time order and the deepest one is the max drawdown
+	episodes := FindDrawdowns(rs.Values)
+	assert.Equal(t, 15, len(episodes))
+	for i := 1; i < len(episodes); i++ {
+		assert.Greater(t, episodes[i].Start, episodes[i-1].Start)
+	}
+	assert.InDelta(t, -MaxDrawdown(rs.Values), SortDrawdowns(episodes)[0].Depth, 0.0000001)
+
+	// these rows are from table.Drawdowns(managers[,1]) in R
+	table := rs.DrawdownTable(5)
+	assert.Equal(t, 5, len(table))
+	expected := []DrawdownRecord{
+		{date(2002, 2, 28), date(2003, 2, 28), date(2003, 7, 31), true, -0.1518, 18, 13, 5},
+		{date(1998, 5, 31), date(1998, 8, 31), date(1999, 3, 31), true, -0.1239, 11, 4, 7},
+		{date(2005, 3, 31), date(2005, 4, 30), date(2005, 9, 30), true, -0.0412, 7, 2, 5},
+		{date(2001, 9, 30), date(2001, 9, 30), date(2001, 11, 30), true, -0.0312, 3, 1, 2},
+		{date(1996, 4, 30), date(1996, 7, 31), date(1996, 8, 31), true, -0.0284, 5, 4, 1},
+	}
+	for i, row := range expected {
+		assert.Equal(t, row.From, table[i].From)
+		assert.Equal(t, row.Trough, table[i].Trough)
+		assert.Equal(t, row.To, table[i].To)
+		assert.Equal(t, row.Recovered, table[i].Recovered)
+		assert.InDelta(t, row.Depth, table[i].Depth, 0.00005)
+		assert.Equal(t, row.Length, table[i].Length)
+		assert.Equal(t, row.ToTrough, table[i].ToTrough)
+		assert.Equal(t, row.Recovery, table[i].Recovery)
+	}
+	assert.Equal(t, 15, len(rs.DrawdownTable(0)))
+
+	// the bacon portfolio ends in an open drawdown
+	bacon, err := ReadSeries("../data/portfolio_bacon.csv", "portfolio monthly return (%)")
+	assert.Nil(t, err)
+	open := bacon.DrawdownTable(1)[0]
+	assert.False(t, open.Recovered)
+	assert.True(t, open.To.IsZero())
+	assert.Equal(t, date(2001, 2, 28), open.From)
+	assert.Equal(t, date(2001, 6, 30), open.Trough)
+	assert.InDelta(t, -0.1446730, open.Depth, 0.0000001)
+	assert.Equal(t, 11, open.Length)
+	assert.Equal(t, 5, open.ToTrough)
+	assert.Equal(t, 0, open.Recovery)
+}
+
+// Test the drawdown based ratios on the Bacon portfolio
+func TestDrawdownRatios(t *testing.T) {
+	bacon, err := ReadFrame("../data/portfolio_bacon.csv")
+	if err != nil {
+		panic(err)
+	}
+	rt, _ := bacon.Column("portfolio monthly return (%)")
+
+	// these numbers are from the R code, rounded as in the package examples
+	assert.InDelta(t, 0.04, PainIndex(rt), 0.0005)
+	assert.InDelta(t, 2.59, PainRatio(rt, 0, 12), 0.005)
+	assert.InDelta(t, 1.70, MartinRatio(rt, 0, 12), 0.01)
+
+	// more decimal places from the same formulas
+	assert.InDelta(t, 0.03998969, PainIndex(rt), 0.0000001)
+	assert.InDelta(t, 0.06118429, UlcerIndex(rt), 0.0000001)
+	assert.InDelta(t, 0.716639, CalmarRatio(rt, 12), 0.000001)
+	assert.InDelta(t, 0.423742, SterlingRatio(rt, 12, 0.1), 0.000001)
+	assert.InDelta(t, 1.694525, MartinRatio(rt, 0, 12), 0.000001)
+	assert.InDelta(t, 2.592625, PainRatio(rt, 0, 12), 0.000001)
+
+	// ! these numbers (0.756221, 3.704711) are Not!!!!! from the R code
+	// ! the R code compounds r/100 inside the negative runs and gives 0.74 and 3.65
+	// ! here the runs are compounded on the returns themselves
+	assert.InDelta(t, 0.756221, BurkeRatio(rt, 0, 12, false), 0.000001)
+	assert.InDelta(t, 3.704711, BurkeRatio(rt, 0, 12, true), 0.000001)
+
+	rs, err := ReadSeries("../data/managers.csv", "HAM1")
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.906170, rs.CalmarRatio(), 0.000001)
+	assert.InDelta(t, 0.546254, rs.SterlingRatio(0.1), 0.000001)
+	assert.InDelta(t, 3.789545, rs.MartinRatio(0), 0.000001)
+	assert.InDelta(t, 8.560097, rs.PainRatio(0), 0.000001)
+	assert.InDelta(t, 0.670930, rs.BurkeRatio(0, false), 0.000001)
+}
@@ -0,0 +1,81 @@
+package statistics
+
+import "fmt"
+
+// the typed errors returned by the Try functions and the loaders
+// inspect them with errors.As, e.g.
+//
+//	var pe *ParseError
+//	if errors.As(err, &pe) { ... pe.Row, pe.Column ... }
+
+// - ColumnNotFoundError is returned when a named column is not in the header
+type ColumnNotFoundError struct {
+	Column string
+}
+
+func (e *ColumnNotFoundError) Error() string {
+	return fmt.Sprintf("column %q not found", e.Column)
+}
+
+// - ParseError is returned when a cell cannot be parsed
+// Row is the 1-based record number after the header
+type ParseError struct {
+	Row    int
+	Column string
+	Value  string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("row %d: cannot parse %q: %v", e.Row, e.Value, e.Err)
+	}
+	return fmt.Sprintf("row %d column %q: cannot parse %q: %v", e.Row, e.Column, e.Value, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// - LengthMismatchError is returned when two inputs must have the same length
+type LengthMismatchError struct {
+	What     string
+	Expected int
+	Got      int
+}
+
+func (e *LengthMismatchError) Error() string {
+	return fmt.Sprintf("%s: expected length %d, got %d", e.What, e.Expected, e.Got)
+}
+
+// - TypeError is returned by the helpers taking a float64 or a []float64 as interface{}
+type TypeError struct {
+	Value interface{}
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("invalid type %T, expected float64 or []float64", e.Value)
+}
+
+// - RankDeficientError is returned by OLS.Run when the columns of X are
+// linearly dependent, Aliased holds the indexes of the dependent columns
+type RankDeficientError struct {
+	Rank    int
+	Columns int
+	Aliased []int
+}
+
+func (e *RankDeficientError) Error() string {
+	return fmt.Sprintf("design matrix has rank %d with %d columns, aliased columns %v", e.Rank, e.Columns, e.Aliased)
+}
+
+// - ConvergenceError is returned by the iterative fits that reach their
+// iteration cap before the tolerance, the last iterate is kept in the model
+type ConvergenceError struct {
+	What       string
+	Iterations int
+}
+
+func (e *ConvergenceError) Error() string {
+	return fmt.Sprintf("%s: no convergence after %d iterations", e.What, e.Iterations)
+}
@@ -0,0 +1,80 @@
+package statistics
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the typed errors of the Try functions and the loaders
+func TestTypedErrors(t *testing.T) {
+	// a missing file is the os error
+	_, _, err := TryReadData("../data/missing.csv")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	_, err = ReadFrame("../data/missing.csv")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+
+	// a missing column
+	var cnf *ColumnNotFoundError
+	_, _, err = TryReadCSV("data.csv", true, "index_price", "interest_rate", "inflation")
+	assert.True(t, errors.As(err, &cnf))
+	assert.Equal(t, "inflation", cnf.Column)
+	_, _, err = TryReadCSV("data.csv", true, "price", "interest_rate")
+	assert.True(t, errors.As(err, &cnf))
+	assert.Equal(t, "price", cnf.Column)
+	_, err = CheckPos(fds, "HAM9")
+	assert.True(t, errors.As(err, &cnf))
+	assert.Panics(t, func() { ReadCSV("data.csv", true, "index_price", "inflation") })
+
+	// without intercept X only holds the named columns
+	X, _, err := TryReadCSV("data.csv", false, "index_price", "interest_rate", "unemployment_rate")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, X.RawMatrix().Cols)
+	assert.Equal(t, 2.75, X.At(0, 0))
+
+	// a parse failure keeps the row and the column
+	var pe *ParseError
+	rtp, _ := CheckPos(fds, "HAM2")
+	_, err = TryStringToFloatSlice(GetSecondDimensionData(dt, rtp))
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, 1, pe.Row)
+	_, _, err = TryReadCSV("../data/managers.csv", true, "HAM2", "HAM1")
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, 1, pe.Row)
+	assert.Equal(t, "HAM2", pe.Column)
+
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.csv")
+	assert.Nil(t, os.WriteFile(bad, []byte(",A,B\n2020-01-31,0.1,0.2\n2020-02-29,0.1,x\n"), 0o644))
+	_, err = ReadFrame(bad)
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, 2, pe.Row)
+	assert.Equal(t, "B", pe.Column)
+	badDate := filepath.Join(dir, "date.csv")
+	assert.Nil(t, os.WriteFile(badDate, []byte(",A\n2020-01-31,0.1\n31/02/2020,0.1\n"), 0o644))
+	_, err = ReadFrame(badDate)
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, 2, pe.Row)
+
+	// length mismatch and invalid types of the interface{} helpers
+	var lm *LengthMismatchError
+	var te *TypeError
+	_, err = TryCumProdAdd([]float64{0.1, 0.2}, []float64{1})
+	assert.True(t, errors.As(err, &lm))
+	assert.Equal(t, 2, lm.Expected)
+	assert.Equal(t, 1, lm.Got)
+	_, err = TryCumProdAdd([]float64{0.1, 0.2}, "1")
+	assert.True(t, errors.As(err, &te))
+	assert.Panics(t, func() { CumProdAdd([]float64{0.1}, 1) })
+	rc := ReturnsCalculator{[]float64{0.1, 0.2}}
+	_, err = rc.TryExcess([]float64{0.1})
+	assert.True(t, errors.As(err, &lm))
+	_, err = rc.TryExcess(1)
+	assert.True(t, errors.As(err, &te))
+	assert.Nil(t, rc.Excess(1))
+	_, err = NewReturnSeries(nil, []float64{0.1})
+	assert.True(t, errors.As(err, &lm))
+}
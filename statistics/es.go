@@ -0,0 +1,77 @@
+package statistics
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// - ES function
+// Expected Shortfall (also known as CVaR or ETL) at confidence level p
+// it is the mean return beyond the VaR, with the same sign convention
+// as VaR: a loss is reported as a negative number
+// tag selects the method:
+// historical for the mean of the returns at or below the historical VaR,
+// gaussian for mean - standard deviation * phi(z) / (1 - p), the 1/n one as in ES.Gaussian,
+// modified for the Cornish-Fisher/Edgeworth expansion of Boudt, Peterson and Croux (2008)
+// any other tag falls back to modified, the PerformanceAnalytics default
+func ES(R []float64, p float64, tag string) float64 {
+	if len(R) == 0 || p <= 0 || p >= 1 {
+		return math.NaN()
+	}
+	alpha := 1 - p
+	switch tag {
+	case "historical":
+		v := VaR(R, p, "historical")
+		sum, count := 0.0, 0
+		for _, r := range R {
+			if r <= v {
+				sum += r
+				count++
+			}
+		}
+		return sum / float64(count)
+	case "gaussian":
+		z := distuv.UnitNormal.Quantile(alpha)
+		return stat.Mean(R, nil) - math.Sqrt(centralMoment(R, 2))*distuv.UnitNormal.Prob(z)/alpha
+	default:
+		skew := Skewness(R, "moment")
+		exkurt := Kurtosis(R, "excess")
+		return stat.Mean(R, nil) + modifiedTail(alpha, skew, exkurt)*math.Sqrt(centralMoment(R, 2))
+	}
+}
+
+// * function for the standardized modified ES
+// the Edgeworth expectation below the Cornish-Fisher quantile, bounded
+// by the quantile itself so that the ES is never smaller than the VaR
+func modifiedTail(alpha, skew, exkurt float64) float64 {
+	h := cornishFisher(alpha, skew, exkurt)
+	return math.Min(edgeworthTail(h, alpha, skew, exkurt), h)
+}
+
+// * function for the expectation of the Edgeworth density below h, divided by alpha
+// the density is phi(u) * (1 + S/6 He3(u) + K/24 He4(u) + S^2/72 He6(u))
+func edgeworthTail(h, alpha, skew, exkurt float64) float64 {
+	m := truncatedMoments(h, 7)
+	e := m[1] +
+		skew/6*(m[4]-3*m[2]) +
+		exkurt/24*(m[5]-6*m[3]+3*m[1]) +
+		skew*skew/72*(m[7]-15*m[5]+45*m[3]-15*m[1])
+	return e / alpha
+}
+
+// * function for the moments of the standard normal truncated above at h
+// m[q] is the integral of u^q phi(u) from -Inf to h, for q = 0..order
+func truncatedMoments(h float64, order int) []float64 {
+	m := make([]float64, order+1)
+	phi := distuv.UnitNormal.Prob(h)
+	m[0] = distuv.UnitNormal.CDF(h)
+	if order > 0 {
+		m[1] = -phi
+	}
+	for q := 2; q <= order; q++ {
+		m[q] = -math.Pow(h, float64(q-1))*phi + float64(q-1)*m[q-2]
+	}
+	return m
+}
@@ -0,0 +1,48 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/stat"
+)
+
+// Test the ES methods on the edhec columns
+func TestES(t *testing.T) {
+	rf, err := ReadFrame("../data/edhec.csv")
+	if err != nil {
+		panic(err)
+	}
+	ca, _ := rf.Column("Convertible Arbitrage")
+	cta, _ := rf.Column("CTA Global")
+	em, _ := rf.Column("Emerging Markets")
+
+	// reference values of the PerformanceAnalytics calls
+	//   ES(edhec[, "Convertible Arbitrage"], p = 0.95, method = "historical")
+	//   ES(edhec[, "CTA Global"], p = 0.95, method = "gaussian")
+	//   ES(edhec[, "Emerging Markets"], p = 0.95, method = "modified")
+	// and so on; R is not available to the test suite, the numbers come from
+	// testdata/reference/es.py which follows ES.historical, ES.Gaussian and
+	// mES, rerun the R calls to check them
+	assert.InDelta(t, -0.0487750, ES(ca, 0.95, "historical"), 0.0000001)
+	assert.InDelta(t, -0.1132000, ES(ca, 0.99, "historical"), 0.0000001)
+	assert.InDelta(t, -0.0348071993, ES(ca, 0.95, "gaussian"), 0.0000000001)
+	assert.InDelta(t, -0.0995476800, ES(ca, 0.95, "modified"), 0.0000000001)
+	assert.InDelta(t, -0.0430777778, ES(cta, 0.95, "historical"), 0.0000000001)
+	assert.InDelta(t, -0.0451775553, ES(cta, 0.95, "gaussian"), 0.0000000001)
+	assert.InDelta(t, -0.0428418456, ES(cta, 0.95, "modified"), 0.0000000001)
+	assert.InDelta(t, -0.0557259495, ES(cta, 0.99, "modified"), 0.0000000001)
+	assert.InDelta(t, -0.1278870181, ES(em, 0.95, "modified"), 0.0000000001)
+
+	// the modified ES is bounded by the modified VaR
+	assert.InDelta(t, VaR(ca, 0.99, "modified"), ES(ca, 0.99, "modified"), 0.0000000001)
+	for _, tag := range []string{"historical", "gaussian", "modified"} {
+		assert.LessOrEqual(t, ES(em, 0.95, tag), VaR(em, 0.95, tag))
+	}
+
+	// without skewness and excess kurtosis the expansion is the normal tail
+	assert.InDelta(t, -2.0627128, modifiedTail(0.05, 0, 0), 0.0000001)
+	assert.InDelta(t, ES(em, 0.99, "gaussian"),
+		stat.Mean(em, nil)+modifiedTail(0.01, 0, 0)*math.Sqrt(centralMoment(em, 2)), 1e-15)
+}
@@ -0,0 +1,270 @@
+package statistics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// the factor sets of the usual models, named as the columns of the
+// Kenneth French data library files (the lookup ignores the case)
+var (
+	FamaFrench3 = []string{"Mkt-RF", "SMB", "HML"}
+	Carhart4    = []string{"Mkt-RF", "SMB", "HML", "MOM"}
+	FamaFrench5 = []string{"Mkt-RF", "SMB", "HML", "RMW", "CMA"}
+)
+
+// - define a factor model of a fund on a frame of factor returns
+// the fund return in excess of the risk free column is regressed on the factors
+// over the dates where the fund, the factors and the risk free rate are all known
+// the dates are joined exactly: monthly returns stamped at month end need factors
+// stamped at month end too, as ReadFrenchFactors gives for the YYYYMM dates of
+// the Kenneth French files; a factor file stamped at the first of the month
+// shares no date with them and Fit reports it
+type FactorModel struct {
+	Fund    *ReturnSeries
+	Factors *ReturnFrame
+
+	factorNames []string
+	riskFree    string
+	factorScale float64
+	covType     CovType
+	maxLags     int
+}
+
+type OptionFactor func(*FactorModel)
+
+// * for the factor columns, FamaFrench3 by default
+func WithFactors(names ...string) OptionFactor {
+	return func(fm *FactorModel) {
+		fm.factorNames = names
+	}
+}
+
+// * for the risk free column, RF by default; an empty name regresses the raw fund
+// return, for factor sets that are not excess returns
+func WithRiskFree(name string) OptionFactor {
+	return func(fm *FactorModel) {
+		fm.riskFree = name
+	}
+}
+
+// * for the multiplier of the factor and risk free columns, 1 by default
+// use 0.01 for the Kenneth French files, which are in percent
+func WithFactorScale(scale float64) OptionFactor {
+	return func(fm *FactorModel) {
+		fm.factorScale = scale
+	}
+}
+
+// * for the covariance estimator of the loadings, HAC with AutoLags by default
+func WithFactorCovType(ct CovType, maxLags int) OptionFactor {
+	return func(fm *FactorModel) {
+		fm.covType = ct
+		fm.maxLags = maxLags
+	}
+}
+
+// NewFactorModel creates a new factor model of the fund on the factor frame
+func NewFactorModel(fund *ReturnSeries, factors *ReturnFrame, opts ...OptionFactor) *FactorModel {
+	fm := &FactorModel{
+		Fund:        fund,
+		Factors:     factors,
+		factorNames: FamaFrench3,
+		riskFree:    "RF",
+		factorScale: 1,
+		covType:     HAC,
+		maxLags:     AutoLags,
+	}
+	for _, opt := range opts {
+		opt(fm)
+	}
+	return fm
+}
+
+// - define the factor attribution of a fund
+type FactorResult struct {
+	// Alpha is the intercept per period, AnnualizedAlpha is Alpha * scale
+	Alpha           float64
+	AnnualizedAlpha float64
+	AlphaTStat      float64
+	AlphaPValue     float64
+	// Factors name the loadings, in the order of the regression
+	Factors  []string
+	Loadings []float64
+	TStats   []float64
+	PValues  []float64
+
+	RSquared    float64
+	AdjRSquared float64
+	// NObs is the number of aligned periods, from Start to End
+	NObs  int
+	Start time.Time
+	End   time.Time
+	// OLS is the fitted regression, for the summaries and the diagnostics
+	OLS *OLS
+}
+
+// method Fit aligns the fund on the factors and runs the regression
+func (fm *FactorModel) Fit() (*FactorResult, error) {
+	if len(fm.factorNames) == 0 {
+		return nil, errors.New("factor model: no factor")
+	}
+	cols := make([][]float64, len(fm.factorNames))
+	for j, name := range fm.factorNames {
+		col, err := fm.column(name)
+		if err != nil {
+			return nil, err
+		}
+		cols[j] = col
+	}
+	var rf []float64
+	if fm.riskFree != "" {
+		col, err := fm.column(fm.riskFree)
+		if err != nil {
+			return nil, err
+		}
+		rf = col
+	}
+
+	// the rows of the frame where the fund and every column are known
+	fund := make(map[time.Time]float64, fm.Fund.Len())
+	for i, d := range fm.Fund.Dates {
+		fund[d] = fm.Fund.Values[i]
+	}
+	rows := make([]int, 0, fm.Factors.Len())
+	common := 0
+	for i, d := range fm.Factors.Dates {
+		if _, ok := fund[d]; !ok {
+			continue
+		}
+		common++
+		if rf != nil && math.IsNaN(rf[i]) {
+			continue
+		}
+		complete := true
+		for _, col := range cols {
+			if math.IsNaN(col[i]) {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			rows = append(rows, i)
+		}
+	}
+	if common == 0 && fm.Fund.Len() > 0 && fm.Factors.Len() > 0 {
+		return nil, fmt.Errorf("factor model: no common date between %s (first %s) and the factors (first %s), the dates must match exactly",
+			fm.Fund.Name, fm.Fund.Start().Format(DateLayout), fm.Factors.Dates[0].Format(DateLayout))
+	}
+	k := len(cols) + 1
+	if len(rows) <= k {
+		return nil, errors.New("factor model: not enough aligned periods")
+	}
+
+	X := mat.NewDense(len(rows), k, nil)
+	Y := mat.NewDense(len(rows), 1, nil)
+	for r, i := range rows {
+		X.Set(r, 0, 1)
+		for j, col := range cols {
+			X.Set(r, j+1, col[i]*fm.factorScale)
+		}
+		y := fund[fm.Factors.Dates[i]]
+		if rf != nil {
+			y -= rf[i] * fm.factorScale
+		}
+		Y.Set(r, 0, y)
+	}
+	yName := fm.Fund.Name
+	if rf != nil {
+		yName += " - " + fm.riskFree
+	}
+	ols := NewOLS(X, Y,
+		WithCovType(fm.covType),
+		WithMaxLags(fm.maxLags),
+		WithNames(yName, append([]string{"alpha"}, fm.factorNames...)...))
+	if err := ols.Run(); err != nil {
+		return nil, err
+	}
+
+	res := &FactorResult{
+		Alpha:       ols.Coefficients().At(0, 0),
+		AlphaTStat:  ols.TStats().At(0, 0),
+		AlphaPValue: ols.PValues().At(0, 0),
+		Factors:     fm.factorNames,
+		Loadings:    make([]float64, len(cols)),
+		TStats:      make([]float64, len(cols)),
+		PValues:     make([]float64, len(cols)),
+		RSquared:    ols.RSquared(),
+		AdjRSquared: ols.AdjRSquared(),
+		NObs:        len(rows),
+		Start:       fm.Factors.Dates[rows[0]],
+		End:         fm.Factors.Dates[rows[len(rows)-1]],
+		OLS:         ols,
+	}
+	res.AnnualizedAlpha = res.Alpha * float64(fm.Fund.Periodicity.Scale())
+	for j := range cols {
+		res.Loadings[j] = ols.Coefficients().At(j+1, 0)
+		res.TStats[j] = ols.TStats().At(j+1, 0)
+		res.PValues[j] = ols.PValues().At(j+1, 0)
+	}
+	return res, nil
+}
+
+// * method for a column of the factor frame, matching the name without case
+func (fm *FactorModel) column(name string) ([]float64, error) {
+	for j, n := range fm.Factors.Names {
+		if strings.EqualFold(n, name) {
+			return fm.Factors.Columns[j], nil
+		}
+	}
+	return nil, &ColumnNotFoundError{Column: name}
+}
+
+// - ReadFrenchFactors function
+// ReadFrenchFactors reads a csv file of the Kenneth French data library as it
+// is downloaded: the text above the header line (the one starting with a comma)
+// is skipped and the reading stops at the end of the first block, so the annual
+// factors and the copyright line that follow the monthly ones are left out
+// the returns stay in percent, see WithFactorScale
+func ReadFrenchFactors(path string) (*ReturnFrame, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var fields []string
+	dt := make([][]string, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		row := strings.Split(scanner.Text(), ",")
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+		if fields == nil {
+			if len(row) > 1 && row[0] == "" && row[1] != "" {
+				fields = row
+			}
+			continue
+		}
+		// a blank line or a line without a YYYYMM or YYYYMMDD date ends the block
+		if len(row[0]) != 6 && len(row[0]) != 8 || !isDigits(row[0]) {
+			break
+		}
+		dt = append(dt, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return nil, fmt.Errorf("%s: no header line starting with a comma", path)
+	}
+	return NewReturnFrame(dt, fields)
+}
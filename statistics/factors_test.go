@@ -0,0 +1,130 @@
+package statistics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test a custom factor model on managers.csv
+// expected values of the statsmodels call
+//
+//	OLS(ham1 - rf, add_constant(managers[["SP500 TR", "US 10Y TR"]])).fit(cov_type='HAC', cov_kwds={'maxlags': 4})
+//
+// from testdata/reference/factors.py as statsmodels is not available to the test suite
+func TestFactorModel(t *testing.T) {
+	fund, err := ReadSeries("../data/managers.csv", "HAM1")
+	assert.NoError(t, err)
+	factors, err := ReadFrame("../data/managers.csv")
+	assert.NoError(t, err)
+
+	fm := NewFactorModel(fund, factors,
+		WithFactors("SP500 TR", "US 10Y TR"),
+		WithRiskFree("US 3m TR"))
+	res, err := fm.Fit()
+	assert.NoError(t, err)
+	assert.Equal(t, 132, res.NObs)
+	assert.Equal(t, fund.Start(), res.Start)
+	assert.Equal(t, 4, res.OLS.Lags())
+	assert.InDelta(t, 0.0057233278, res.Alpha, 1e-10)
+	assert.InDelta(t, 0.0686799341, res.AnnualizedAlpha, 1e-10)
+	assert.InDelta(t, 2.99897588, res.AlphaTStat, 1e-7)
+	assert.InDelta(t, 0.0027088875, res.AlphaPValue, 1e-9)
+	assert.InDelta(t, 0.3704361181, res.Loadings[0], 1e-10)
+	assert.InDelta(t, -0.2364624246, res.Loadings[1], 1e-10)
+	assert.InDelta(t, 6.99785030, res.TStats[0], 1e-7)
+	assert.InDelta(t, -3.38497206, res.TStats[1], 1e-7)
+	assert.InDelta(t, 0.0007118550, res.PValues[1], 1e-9)
+	assert.InDelta(t, 0.4663463462, res.RSquared, 1e-9)
+	summary, err := res.OLS.Summary()
+	assert.NoError(t, err)
+	assert.Contains(t, summary, "HAM1 - US 3m TR")
+
+	_, err = NewFactorModel(fund, factors).Fit()
+	var cnf *ColumnNotFoundError
+	assert.ErrorAs(t, err, &cnf)
+}
+
+// test a Fama-French style file with YYYYMM dates and percent returns
+func TestFactorModelFrenchFile(t *testing.T) {
+	fund, err := ReadSeries("../data/managers.csv", "HAM1")
+	assert.NoError(t, err)
+	managers, err := ReadFrame("../data/managers.csv")
+	assert.NoError(t, err)
+	sp, _ := managers.Column("SP500 TR")
+	bond, _ := managers.Column("US 10Y TR")
+	ham4, _ := managers.Column("HAM4")
+	cash, _ := managers.Column("US 3m TR")
+
+	// the factors in percent as downloaded, the last year missing from the file,
+	// between the preamble and the annual block
+	var sb strings.Builder
+	sb.WriteString("This file was created by CMPT_ME_BEME_RETS using the 202401 CRSP database.\r\n")
+	sb.WriteString("The 1-month TBill return is from Ibbotson and Associates, Inc.\r\n\r\n")
+	sb.WriteString(",Mkt-RF,SMB,HML,RF\r\n")
+	for i, d := range managers.Dates[:120] {
+		fmt.Fprintf(&sb, "%s,%10.6f,%10.6f,%10.6f,%10.6f\r\n", d.Format("200601"),
+			(sp[i]-cash[i])*100, bond[i]*100, ham4[i]*100, cash[i]*100)
+	}
+	sb.WriteString("\r\n Annual Factors: January-December \r\n")
+	sb.WriteString(",Mkt-RF,SMB,HML,RF\r\n")
+	sb.WriteString("  1996,   20.00,   -2.00,    3.00,    5.00\r\n\r\n")
+	sb.WriteString("Copyright 2024 Kenneth R. French\r\n")
+	path := filepath.Join(t.TempDir(), "F-F_Research_Data_Factors.csv")
+	assert.NoError(t, os.WriteFile(path, []byte(sb.String()), 0o644))
+	factors, err := ReadFrenchFactors(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 120, factors.Len())
+	assert.Equal(t, []string{"Mkt-RF", "SMB", "HML", "RF"}, factors.Names)
+	assert.Equal(t, time.Date(1996, 1, 31, 0, 0, 0, 0, time.UTC), factors.Dates[0])
+	assert.Equal(t, managers.Dates[119], factors.Dates[119])
+	// the raw file is not a csv that ReadFrame can take
+	_, err = ReadFrame(path)
+	assert.Error(t, err)
+
+	res, err := NewFactorModel(fund, factors, WithFactorScale(0.01)).Fit()
+	assert.NoError(t, err)
+	assert.Equal(t, 120, res.NObs)
+	assert.Equal(t, []string{"Mkt-RF", "SMB", "HML"}, res.Factors)
+
+	// the same regression from the unscaled managers columns
+	mkt := make([]float64, len(sp))
+	for i := range sp {
+		mkt[i] = sp[i] - cash[i]
+	}
+	custom := &ReturnFrame{
+		Dates:   managers.Dates[:120],
+		Names:   []string{"MKT", "BOND", "HAM4", "RF"},
+		Columns: [][]float64{mkt[:120], bond[:120], ham4[:120], cash[:120]},
+	}
+	direct, err := NewFactorModel(fund, custom, WithFactors("mkt", "bond", "ham4")).Fit()
+	assert.NoError(t, err)
+	assert.InDelta(t, direct.Alpha, res.Alpha, 1e-8)
+	for j := range res.Loadings {
+		assert.InDelta(t, direct.Loadings[j], res.Loadings[j], 1e-6)
+	}
+
+	// Carhart needs a momentum column
+	_, err = NewFactorModel(fund, factors, WithFactors(Carhart4...)).Fit()
+	assert.Error(t, err)
+
+	// factors stamped at the first of the month share no date with the fund
+	firsts := make([]time.Time, len(custom.Dates))
+	for i, d := range custom.Dates {
+		firsts[i] = time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	shifted := &ReturnFrame{Dates: firsts, Names: custom.Names, Columns: custom.Columns}
+	_, err = NewFactorModel(fund, shifted, WithFactors("mkt")).Fit()
+	assert.ErrorContains(t, err, "no common date")
+
+	_, err = ReadFrenchFactors(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.Error(t, err)
+	assert.NoError(t, os.WriteFile(path, []byte("no header here\n1,2\n"), 0o644))
+	_, err = ReadFrenchFactors(path)
+	assert.Error(t, err)
+}
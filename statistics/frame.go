@@ -0,0 +1,220 @@
+package statistics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// - define a struct to hold several return columns on a shared date index
+// missing cells are kept as math.NaN() so every column has the same length
+type ReturnFrame struct {
+	// Dates is the shared time index
+	Dates []time.Time
+	// Names are the column names in file order, without the date column
+	Names []string
+	// Columns holds one slice per name, NaN marks a missing value
+	Columns [][]float64
+	// Periodicity is inferred from the dates
+	Periodicity Periodicity
+}
+
+// NewReturnFrame builds a frame from the output of ReadData
+// the first column must hold the dates, blank and NA cells become NaN
+func NewReturnFrame(dt [][]string, fields []string) (*ReturnFrame, error) {
+	if len(fields) < 2 {
+		return nil, errors.New("frame needs a date column and at least one value column")
+	}
+	rf := &ReturnFrame{
+		Dates:   make([]time.Time, len(dt)),
+		Names:   make([]string, len(fields)-1),
+		Columns: make([][]float64, len(fields)-1),
+	}
+	for j := range rf.Names {
+		rf.Names[j] = strings.TrimSpace(fields[j+1])
+		rf.Columns[j] = make([]float64, len(dt))
+	}
+	for i, row := range dt {
+		if len(row) != len(fields) {
+			return nil, &LengthMismatchError{What: fmt.Sprintf("row %d", i+1), Expected: len(fields), Got: len(row)}
+		}
+		d, err := parseDate(row[0])
+		if err != nil {
+			return nil, &ParseError{Row: i + 1, Column: fields[0], Value: row[0], Err: err}
+		}
+		if i > 0 && !d.After(rf.Dates[i-1]) {
+			return nil, fmt.Errorf("row %d: dates are not strictly increasing", i+1)
+		}
+		rf.Dates[i] = d
+		for j := range rf.Names {
+			v, err := parseCell(row[j+1])
+			if err != nil {
+				return nil, &ParseError{Row: i + 1, Column: rf.Names[j], Value: row[j+1], Err: err}
+			}
+			rf.Columns[j][i] = v
+		}
+	}
+	rf.Periodicity = InferPeriodicity(rf.Dates)
+	return rf, nil
+}
+
+// ReadFrame reads a csv file with a date column followed by return columns
+func ReadFrame(path string) (*ReturnFrame, error) {
+	dt, fields, err := TryReadData(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReturnFrame(dt, fields)
+}
+
+// * function to parse one date cell
+// it accepts DateLayout, YYYY-MM and YYYYMM (both at the month end, as the
+// monthly Kenneth French factor files) and YYYYMMDD (the daily ones)
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case len(s) == 6 && isDigits(s):
+		return monthEnd(time.Parse("200601", s))
+	case len(s) == 7 && s[4] == '-':
+		return monthEnd(time.Parse("2006-01", s))
+	case len(s) == 8 && isDigits(s):
+		return time.Parse("20060102", s)
+	default:
+		return time.Parse(DateLayout, s)
+	}
+}
+
+// * function for the last day of the month of a parsed date
+func monthEnd(d time.Time, err error) (time.Time, error) {
+	if err != nil {
+		return d, err
+	}
+	return d.AddDate(0, 1, -1), nil
+}
+
+// * function to tell whether s only holds ASCII digits
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// * function to parse one cell, blank and NA cells are missing values
+func parseCell(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "NA" || s == "NaN" {
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// - Method for the number of rows
+func (rf *ReturnFrame) Len() int {
+	return len(rf.Dates)
+}
+
+// - Method for the position of a column
+func (rf *ReturnFrame) Pos(name string) (int, error) {
+	return CheckPos(rf.Names, name)
+}
+
+// - Method for a column by name, NaN included
+func (rf *ReturnFrame) Column(name string) ([]float64, error) {
+	pos, err := rf.Pos(name)
+	if err != nil {
+		return nil, err
+	}
+	return rf.Columns[pos], nil
+}
+
+// - Method for the number of missing values in a column
+func (rf *ReturnFrame) NACount(name string) (int, error) {
+	col, err := rf.Column(name)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, v := range col {
+		if math.IsNaN(v) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// - Method for a column as a return series
+// rows where the column is missing are dropped together with their dates
+func (rf *ReturnFrame) Series(name string) (*ReturnSeries, error) {
+	col, err := rf.Column(name)
+	if err != nil {
+		return nil, err
+	}
+	dates := make([]time.Time, 0, len(col))
+	values := make([]float64, 0, len(col))
+	for i, v := range col {
+		if !math.IsNaN(v) {
+			dates = append(dates, rf.Dates[i])
+			values = append(values, v)
+		}
+	}
+	return NewReturnSeries(dates, values, WithName(name), WithPeriodicity(rf.Periodicity))
+}
+
+// - Method for a new frame with the named columns in the given order
+// the column slices are shared with the original frame
+func (rf *ReturnFrame) Select(names ...string) (*ReturnFrame, error) {
+	sel := &ReturnFrame{
+		Dates:       rf.Dates,
+		Names:       make([]string, len(names)),
+		Columns:     make([][]float64, len(names)),
+		Periodicity: rf.Periodicity,
+	}
+	for j, name := range names {
+		col, err := rf.Column(name)
+		if err != nil {
+			return nil, err
+		}
+		sel.Names[j] = name
+		sel.Columns[j] = col
+	}
+	return sel, nil
+}
+
+// - Method for a new frame keeping only the rows without any missing value
+func (rf *ReturnFrame) DropNA() *ReturnFrame {
+	keep := make([]int, 0, len(rf.Dates))
+	for i := range rf.Dates {
+		complete := true
+		for _, col := range rf.Columns {
+			if math.IsNaN(col[i]) {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			keep = append(keep, i)
+		}
+	}
+	out := &ReturnFrame{
+		Dates:       make([]time.Time, len(keep)),
+		Names:       rf.Names,
+		Columns:     make([][]float64, len(rf.Columns)),
+		Periodicity: rf.Periodicity,
+	}
+	for j := range rf.Columns {
+		out.Columns[j] = make([]float64, len(keep))
+	}
+	for k, i := range keep {
+		out.Dates[k] = rf.Dates[i]
+		for j, col := range rf.Columns {
+			out.Columns[j][k] = col[i]
+		}
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test ReturnFrame loading, NA handling and column selection
+func TestReturnFrame(t *testing.T) {
+	rf, err := ReadFrame("../data/managers.csv")
+	assert.Nil(t, err)
+	assert.Equal(t, 132, rf.Len())
+	assert.Equal(t, 10, len(rf.Names))
+	assert.Equal(t, Monthly, rf.Periodicity)
+
+	// HAM2 starts late, the missing months are explicit NaN
+	ham2, err := rf.Column("HAM2")
+	assert.Nil(t, err)
+	assert.Equal(t, 132, len(ham2))
+	assert.True(t, math.IsNaN(ham2[0]))
+	na, err := rf.NACount("HAM2")
+	assert.Nil(t, err)
+	assert.Equal(t, 7, na)
+
+	// the series drops the missing rows and matches the slice based result
+	rs, err := rf.Series("HAM2")
+	assert.Nil(t, err)
+	assert.Equal(t, 125, rs.Len())
+	assert.InDelta(t, 0.1746569, rs.AnnualizedReturn(true), 0.0000001)
+
+	sel, err := rf.Select("SP500 TR", "HAM1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SP500 TR", "HAM1"}, sel.Names)
+	_, err = rf.Select("HAM1", "HAM9")
+	assert.NotNil(t, err)
+
+	// complete rows start when the last manager starts reporting
+	full := rf.DropNA()
+	assert.Equal(t, full.Len(), len(full.Columns[0]))
+	for _, col := range full.Columns {
+		for _, v := range col {
+			assert.False(t, math.IsNaN(v))
+		}
+	}
+
+	// quoted names are kept, the leading blanks are trimmed
+	edhec, err := ReadFrame("../data/edhec.csv")
+	assert.Nil(t, err)
+	assert.Equal(t, 13, len(edhec.Names))
+	_, err = edhec.Column("CTA Global")
+	assert.Nil(t, err)
+	bacon, err := ReadFrame("../data/portfolio_bacon.csv")
+	assert.Nil(t, err)
+	_, err = bacon.Column("benchmark return (%)")
+	assert.Nil(t, err)
+
+	_, err = ReadFrame("../data/missing.csv")
+	assert.NotNil(t, err)
+}
+
+// test the date formats of the frame files
+func TestParseDate(t *testing.T) {
+	for s, want := range map[string]time.Time{
+		"1996-01-31": time.Date(1996, 1, 31, 0, 0, 0, 0, time.UTC),
+		"199602":     time.Date(1996, 2, 29, 0, 0, 0, 0, time.UTC),
+		"2001-12":    time.Date(2001, 12, 31, 0, 0, 0, 0, time.UTC),
+		"20230315":   time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC),
+		" 202304 ":   time.Date(2023, 4, 30, 0, 0, 0, 0, time.UTC),
+	} {
+		d, err := parseDate(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, want, d, s)
+	}
+	for _, s := range []string{"1996", "199613", "1996/01/31", "2023-1"} {
+		_, err := parseDate(s)
+		assert.Error(t, err, s)
+	}
+}
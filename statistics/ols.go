@@ -1,10 +1,9 @@
 package statistics
 
 import (
-	"encoding/csv"
+	"errors"
 	"fmt"
 	"math"
-	"os"
 	"strconv"
 
 	"gonum.org/v1/gonum/mat"
@@ -35,177 +34,434 @@ type OLS struct {
 	// adjRSquared is the adjusted coefficient of determination
 	adjRSquared float64
 
+	// covType selects the covariance estimator of the coefficients
+	covType CovType
+	// maxLags is the number of lags of the HAC estimator
+	maxLags int
+	// covParams is the covariance matrix of the coefficients
+	covParams *mat.Dense
+	// xTxInv is (X'X)^-1, or its pseudo-inverse when X is rank deficient
+	xTxInv *mat.Dense
+	// sigma2 is the variance of the residuals
+	sigma2 float64
+	// condNumber is the ratio of the largest to the smallest singular value of X
+	condNumber float64
+	// rank is the numerical rank of X
+	rank int
+	// aliased are the indexes of the columns of X linearly dependent on earlier ones
+	aliased []int
+
+	// weights are the observation weights of WLS, nil for OLS
+	weights []float64
+	// wX and wResiduals are X and the residuals scaled by sqrt(weights),
+	// the same matrices as X and the residuals for OLS
+	wX         *mat.Dense
+	wResiduals *mat.Dense
+
+	// model names the estimator in the summaries, OLS, WLS or GLSAR
+	model string
+	// yName and xNames label the dependent and the independent variables
+	yName  string
+	xNames []string
+}
+
+// machineEpsilon is the machine epsilon of float64
+const machineEpsilon = 2.220446049250313e-16
+
+// - define the covariance estimators of the coefficients
+type CovType int
+
+const (
+	// NonRobust is the classical sigma^2 * (X'X)^-1
+	NonRobust CovType = iota
+	// HC0 is White's heteroskedasticity robust estimator
+	HC0
+	// HC1 is HC0 scaled by n / (n - k)
+	HC1
+	// HC2 weights each squared residual by 1 / (1 - h), h the leverage
+	HC2
+	// HC3 weights each squared residual by 1 / (1 - h)^2
+	HC3
+	// HAC is the Newey-West estimator with Bartlett weights
+	HAC
+)
+
+// AutoLags lets the HAC estimator pick floor(4 * (n / 100)^(2/9)) lags
+const AutoLags = -1
+
+// String returns the statsmodels name of the covariance estimator
+func (ct CovType) String() string {
+	switch ct {
+	case HC0:
+		return "HC0"
+	case HC1:
+		return "HC1"
+	case HC2:
+		return "HC2"
+	case HC3:
+		return "HC3"
+	case HAC:
+		return "HAC"
+	default:
+		return "nonrobust"
+	}
+}
+
+type OptionOLS func(*OLS)
+
+// * for the covariance estimator of the coefficients
+func WithCovType(ct CovType) OptionOLS {
+	return func(ols *OLS) {
+		ols.covType = ct
+	}
+}
+
+// * for the number of lags of the HAC estimator, AutoLags by default
+func WithMaxLags(lags int) OptionOLS {
+	return func(ols *OLS) {
+		ols.maxLags = lags
+	}
+}
+
+// * for the names of the dependent and the independent variables
+// used by the summaries, xs must have one name per column of X
+func WithNames(y string, xs ...string) OptionOLS {
+	return func(ols *OLS) {
+		ols.yName = y
+		ols.xNames = xs
+	}
 }
 
 // NewOLS creates a new Ordinary Least Squares model
-func NewOLS(X, Y *mat.Dense) *OLS {
-	return &OLS{
-		X: X,
-		Y: Y,
+func NewOLS(X, Y *mat.Dense, opts ...OptionOLS) *OLS {
+	ols := &OLS{
+		X:       X,
+		Y:       Y,
+		maxLags: AutoLags,
+		model:   "OLS",
 	}
+	for _, opt := range opts {
+		opt(ols)
+	}
+	return ols
 }
 
 // ReadCSV reads a csv file and returns the X and Y matrices
+// it panics on any error, use TryReadCSV to handle them
 func ReadCSV(filename string, Intercept bool, y string, Xs ...string) (*mat.Dense, *mat.Dense) {
-	// read the csv file
-	csvFile, err := os.Open(filename)
+	X, Y, err := TryReadCSV(filename, Intercept, y, Xs...)
 	if err != nil {
 		panic(err)
 	}
-	defer csvFile.Close()
+	return X, Y
+}
 
-	// create a new csv reader
-	reader := csv.NewReader(csvFile)
-	// read the header
-	header, err := reader.Read()
+// ReadOLS reads a csv file into an OLS model named after its columns
+// the intercept column is named const, as in statsmodels
+func ReadOLS(filename string, Intercept bool, y string, Xs []string, opts ...OptionOLS) (*OLS, error) {
+	X, Y, err := TryReadCSV(filename, Intercept, y, Xs...)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	names := make([]string, 0, len(Xs)+1)
+	if Intercept {
+		names = append(names, "const")
 	}
-	// fmt.Println(header)
+	names = append(names, Xs...)
+	return NewOLS(X, Y, append([]OptionOLS{WithNames(y, names...)}, opts...)...), nil
+}
 
-	// read all the records
-	records, err := reader.ReadAll()
+// TryReadCSV reads a csv file and returns the X and Y matrices
+// a missing column gives a *ColumnNotFoundError and a bad cell a *ParseError
+// when Intercept is true the first column of X is filled with ones
+func TryReadCSV(filename string, Intercept bool, y string, Xs ...string) (*mat.Dense, *mat.Dense, error) {
+	// read the csv file
+	records, header, err := TryReadData(filename)
 	if err != nil {
-		panic(err)
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("no records in %s", filename)
 	}
-	// fmt.Println(records)
 
-	// check if the independent variable is in the header
+	// check if the independent variables are in the header
 	// and store the index in a tmp slice
-	var yIndex int
-	var xIndex []int
-	for x := range Xs {
-		found := false
-		for i, h := range header {
-			if h == Xs[x] {
-				found = true
-				xIndex = append(xIndex, i)
-				break
-			}
-		}
-		if !found {
-			panic(fmt.Sprintf("column %s not found in the csv file", Xs[x]))
+	xIndex := make([]int, len(Xs))
+	for j, x := range Xs {
+		pos, err := CheckPos(header, x)
+		if err != nil {
+			return nil, nil, err
 		}
+		xIndex[j] = pos
 	}
-	for i, h := range header {
-		if h == y {
-			yIndex = i
-			break
-		}
+	yIndex, err := CheckPos(header, y)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// create the X and Y matrices
-	X := mat.NewDense(len(records), len(Xs)+1, nil)
+	offset := 0
 	if Intercept {
-		for i := 0; i < len(records); i++ {
+		offset = 1
+	}
+	X := mat.NewDense(len(records), len(Xs)+offset, nil)
+	Y := mat.NewDense(len(records), 1, nil)
+	for i, record := range records {
+		if Intercept {
 			X.Set(i, 0, 1)
-			for j, x := range xIndex {
-				val, err := strconv.ParseFloat(records[i][x], 64)
-				if err != nil {
-					panic(err)
-				}
-				X.Set(i, j+1, val)
-			}
 		}
-	} else {
-		for i := 0; i < len(records); i++ {
-			for j, x := range xIndex {
-				val, err := strconv.ParseFloat(records[i][x], 64)
-				if err != nil {
-					panic(err)
-				}
-				X.Set(i, j, val)
+		for j, x := range xIndex {
+			val, err := strconv.ParseFloat(record[x], 64)
+			if err != nil {
+				return nil, nil, &ParseError{Row: i + 1, Column: Xs[j], Value: record[x], Err: err}
 			}
+			X.Set(i, j+offset, val)
 		}
-	}
-	Y := mat.NewDense(len(records), 1, nil)
-	for i := 0; i < len(records); i++ {
-		val, err := strconv.ParseFloat(records[i][yIndex], 64)
+		val, err := strconv.ParseFloat(record[yIndex], 64)
 		if err != nil {
-			panic(err)
+			return nil, nil, &ParseError{Row: i + 1, Column: y, Value: record[yIndex], Err: err}
 		}
 		Y.Set(i, 0, val)
 	}
-	return X, Y
+	return X, Y, nil
 }
 
 // method Run will do all the OLS model calculations
-func (ols *OLS) Run() {
-	// calculate the matrix Transpose
-	xT := mat.DenseCopyOf(ols.X.T())
-	// calculate the matrix xT * x
-	xTx := mat.NewDense(ols.X.RawMatrix().Cols, ols.X.RawMatrix().Cols, nil)
-	xTx.Product(xT, ols.X)
+// the least squares problem is solved through the singular value decomposition
+// of X rather than by inverting X'X, which squares the condition number
+// when X has not full column rank Run returns a *RankDeficientError, the
+// results are then those of the minimum norm solution (the pseudo-inverse)
+func (ols *OLS) Run() error {
+	n, k := ols.X.Dims()
+	if ols.xNames != nil && len(ols.xNames) != k {
+		return &LengthMismatchError{What: "variable names", Expected: k, Got: len(ols.xNames)}
+	}
 
-	// calculate the inverse of xTx
-	xTxInv := mat.NewDense(ols.X.RawMatrix().Cols, ols.X.RawMatrix().Cols, nil)
-	xTxInv.Inverse(xTx)
+	// scale the rows by the square root of the weights, for WLS
+	wX, wY := ols.X, ols.Y
+	if ols.weights != nil {
+		if len(ols.weights) != n {
+			return &LengthMismatchError{What: "weights", Expected: n, Got: len(ols.weights)}
+		}
+		wX = mat.DenseCopyOf(ols.X)
+		wY = mat.DenseCopyOf(ols.Y)
+		for i, w := range ols.weights {
+			if w <= 0 {
+				return errors.New("ols: weights must be positive")
+			}
+			sw := math.Sqrt(w)
+			for j := 0; j < k; j++ {
+				wX.Set(i, j, wX.At(i, j)*sw)
+			}
+			wY.Set(i, 0, wY.At(i, 0)*sw)
+		}
+	}
+	ols.wX = wX
 
-	// calculate the matrix xT * y
-	xTy := mat.NewDense(ols.X.RawMatrix().Cols, 1, nil)
-	xTy.Product(xT, ols.Y)
+	// factorize X = U * S * V'
+	var svd mat.SVD
+	if !svd.Factorize(wX, mat.SVDThin) {
+		return errors.New("ols: singular value decomposition failed")
+	}
+	values := svd.Values(nil)
+	var u, v mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+
+	// singular values below the tolerance are treated as zero,
+	// the same rule as numpy.linalg.matrix_rank
+	tol := float64(max(n, k)) * values[0] * machineEpsilon
+	ols.rank = 0
+	for _, s := range values {
+		if s > tol {
+			ols.rank++
+		}
+	}
+	ols.condNumber = values[0] / values[len(values)-1]
+	ols.aliased = ols.aliasedColumns(tol)
+
+	// calculate the pseudo-inverse V * S^-1 * U' and (X'X)^-1 = V * S^-2 * V'
+	vInv := mat.DenseCopyOf(&v)
+	vInv2 := mat.DenseCopyOf(&v)
+	for j, s := range values {
+		inv := 0.0
+		if s > tol {
+			inv = 1 / s
+		}
+		for i := 0; i < k; i++ {
+			vInv.Set(i, j, vInv.At(i, j)*inv)
+			vInv2.Set(i, j, vInv2.At(i, j)*inv*inv)
+		}
+	}
+	pinv := mat.NewDense(k, n, nil)
+	pinv.Mul(vInv, u.T())
+	ols.xTxInv = mat.NewDense(k, k, nil)
+	ols.xTxInv.Mul(vInv2, v.T())
 
 	// calculate the matrix beta
-	ols.coefficients = mat.NewDense(ols.X.RawMatrix().Cols, 1, nil)
-	ols.coefficients.Product(xTxInv, xTy)
+	ols.coefficients = mat.NewDense(k, 1, nil)
+	ols.coefficients.Mul(pinv, wY)
 
 	// calculate the matrix yHat
-	ols.yhat = mat.NewDense(ols.Y.RawMatrix().Rows, 1, nil)
+	ols.yhat = mat.NewDense(n, 1, nil)
 	ols.yhat.Product(ols.X, ols.coefficients)
 
 	// calculate the matrix residuals
-	ols.residuals = mat.NewDense(ols.Y.RawMatrix().Rows, 1, nil)
+	ols.residuals = mat.NewDense(n, 1, nil)
 	ols.residuals.Sub(ols.Y, ols.yhat)
+	ols.wResiduals = ols.residuals
+	if ols.weights != nil {
+		ols.wResiduals = mat.NewDense(n, 1, nil)
+		ols.wResiduals.Product(wX, ols.coefficients)
+		ols.wResiduals.Sub(wY, ols.wResiduals)
+	}
 
 	// calculate the σ^2 which is the variance of the residuals
-	// turn residuals into a mat.Vector
-	residualsVec := mat.NewVecDense(ols.Y.RawMatrix().Rows, nil)
-	for i := 0; i < ols.Y.RawMatrix().Rows; i++ {
-		residualsVec.SetVec(i, ols.residuals.At(i, 0))
+	residualsVec := ols.wResiduals.ColView(0)
+	ols.sigma2 = mat.Dot(residualsVec, residualsVec) / float64(n-ols.rank)
+
+	// calculate the covariance matrix of the coefficients
+	switch ols.covType {
+	case HC0, HC1, HC2, HC3:
+		ols.covParams = ols.sandwich(ols.xTxInv, ols.whiteMeat(ols.xTxInv))
+	case HAC:
+		ols.covParams = ols.sandwich(ols.xTxInv, ols.hacMeat(ols.Lags()))
+	default:
+		ols.covParams = mat.NewDense(k, k, nil)
+		ols.covParams.Scale(ols.sigma2, ols.xTxInv)
 	}
-	// calculate the σ^2 which is the variance of the residualsVec
-	sigma2 := mat.Dot(residualsVec, residualsVec) / float64(ols.Y.RawMatrix().Rows-ols.X.RawMatrix().Cols)
 
 	// calculate the matrix of standard errors
-	ols.standardErrors = mat.NewDense(ols.X.RawMatrix().Cols, 1, nil)
-	for i := 0; i < ols.X.RawMatrix().Cols; i++ {
-		ols.standardErrors.Set(i, 0,  math.Sqrt(sigma2*xTxInv.At(i, i)))
+	ols.standardErrors = mat.NewDense(k, 1, nil)
+	for i := 0; i < k; i++ {
+		ols.standardErrors.Set(i, 0, math.Sqrt(ols.covParams.At(i, i)))
 	}
 
 	// calculate the matrix of t statistics
-	ols.tStats = mat.NewDense(ols.X.RawMatrix().Cols, 1, nil)
-	for i := 0; i < ols.X.RawMatrix().Cols; i++ {
+	ols.tStats = mat.NewDense(k, 1, nil)
+	for i := 0; i < k; i++ {
 		ols.tStats.Set(i, 0, ols.coefficients.At(i, 0)/ols.standardErrors.At(i, 0))
 	}
 
 	// calculate the matrix of p values
 	// calculate the degrees of freedom
-	df := float64(ols.Y.RawMatrix().Rows - ols.X.RawMatrix().Cols)
-	ols.pValues = mat.NewDense(ols.X.RawMatrix().Cols, 1, nil)
-	for i := 0; i < ols.X.RawMatrix().Cols; i++ {
+	// robust estimators use the normal distribution, as statsmodels does
+	df := ols.DF()
+	ols.pValues = mat.NewDense(k, 1, nil)
+	for i := 0; i < k; i++ {
+		if ols.covType != NonRobust {
+			ols.pValues.Set(i, 0, 2*(1-distuv.UnitNormal.CDF(math.Abs(ols.tStats.At(i, 0)))))
+			continue
+		}
 		dist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}
 		ols.pValues.Set(i, 0, 2*(1-dist.CDF(math.Abs(ols.tStats.At(i, 0)))))
 	}
 	// calculate the rSquared
-	// calculate the sum of squares total
-	yMean := mat.Sum(ols.Y) / float64(ols.Y.RawMatrix().Rows)
-	ssTotal := mat.NewDense(ols.Y.RawMatrix().Rows, 1, nil)
-	yMeanMat := mat.NewDense(ols.Y.RawMatrix().Rows, 1, nil)
-	for i := 0; i < ols.Y.RawMatrix().Rows; i++ {
-		yMeanMat.Set(i, 0, yMean)
-	}
-	ssTotal.Sub(ols.Y, yMeanMat)
-	ssTotal.MulElem(ssTotal, ssTotal)
-	ssTotalVal := mat.Sum(ssTotal)
+	// calculate the sum of squares total, weighted for WLS
+	ssTotalVal := ols.totalSS(true)
 
 	// calculate the sum of squares residuals
-	ssResiduals := mat.NewDense(ols.residuals.RawMatrix().Rows, 1, nil)
-	ssResiduals.MulElem(ols.residuals, ols.residuals)
-	ssResidualsVal := mat.Sum(ssResiduals)
+	ssResidualsVal := mat.Dot(residualsVec, residualsVec)
 	// calculate the rSquared
 	ols.rSquared = 1 - ssResidualsVal/ssTotalVal
 	// calculate the adjusted rSquared
-	ols.adjRSquared = 1 - (1-ols.rSquared)*(float64(ols.Y.RawMatrix().Rows-1)/float64(ols.Y.RawMatrix().Rows-ols.X.RawMatrix().Cols))
+	ols.adjRSquared = 1 - (1-ols.rSquared)*(float64(n-1)/df)
+
+	if ols.rank < k {
+		return &RankDeficientError{Rank: ols.rank, Columns: k, Aliased: ols.aliased}
+	}
+	return nil
+}
+
+// * method to find the aliased columns of X
+// sequential Gram-Schmidt: a column is aliased when what is left of it after
+// projecting out the earlier kept columns is below the rank tolerance,
+// so the earliest columns are kept and the later duplicates reported
+func (ols *OLS) aliasedColumns(tol float64) []int {
+	n, k := ols.wX.Dims()
+	basis := make([]*mat.VecDense, 0, k)
+	aliased := make([]int, 0)
+	for j := 0; j < k; j++ {
+		r := mat.VecDenseCopyOf(ols.wX.ColView(j))
+		// two passes of modified Gram-Schmidt keep the basis orthogonal
+		for pass := 0; pass < 2; pass++ {
+			for _, q := range basis {
+				r.AddScaledVec(r, -mat.Dot(q, r), q)
+			}
+		}
+		norm := mat.Norm(r, 2)
+		if norm <= tol || n == 0 {
+			aliased = append(aliased, j)
+			continue
+		}
+		r.ScaleVec(1/norm, r)
+		basis = append(basis, r)
+	}
+	return aliased
+}
+
+// * method for the White meat matrix
+// sum of w_t e_t^2 x_t x_t' where w_t depends on the HC variant
+func (ols *OLS) whiteMeat(xTxInv mat.Matrix) *mat.Dense {
+	n, k := ols.wX.Dims()
+	scores := mat.NewDense(n, k, nil)
+	for t := 0; t < n; t++ {
+		e := ols.wResiduals.At(t, 0)
+		weight := 1.0
+		switch ols.covType {
+		case HC1:
+			weight = float64(n) / float64(n-k)
+		case HC2, HC3:
+			// leverage of the observation, x_t' (X'X)^-1 x_t
+			row := ols.wX.RowView(t)
+			h := mat.Inner(row, xTxInv, row)
+			weight = 1 / (1 - h)
+			if ols.covType == HC3 {
+				weight *= weight
+			}
+		}
+		for j := 0; j < k; j++ {
+			scores.Set(t, j, ols.wX.At(t, j)*e*math.Sqrt(weight))
+		}
+	}
+	meat := mat.NewDense(k, k, nil)
+	meat.Mul(scores.T(), scores)
+	return meat
+}
+
+// * method for the Newey-West meat matrix
+// sum of e_t^2 x_t x_t' plus the Bartlett weighted lagged cross products
+func (ols *OLS) hacMeat(lags int) *mat.Dense {
+	n, k := ols.wX.Dims()
+	// the score of each observation, x_t * e_t
+	scores := mat.NewDense(n, k, nil)
+	for t := 0; t < n; t++ {
+		e := ols.wResiduals.At(t, 0)
+		for j := 0; j < k; j++ {
+			scores.Set(t, j, ols.wX.At(t, j)*e)
+		}
+	}
+	meat := mat.NewDense(k, k, nil)
+	meat.Mul(scores.T(), scores)
+	for l := 1; l <= lags && l < n; l++ {
+		weight := 1 - float64(l)/float64(lags+1)
+		var gamma mat.Dense
+		gamma.Mul(scores.Slice(l, n, 0, k).T(), scores.Slice(0, n-l, 0, k))
+		for i := 0; i < k; i++ {
+			for j := 0; j < k; j++ {
+				meat.Set(i, j, meat.At(i, j)+weight*(gamma.At(i, j)+gamma.At(j, i)))
+			}
+		}
+	}
+	return meat
+}
+
+// * method for the sandwich bread * meat * bread
+func (ols *OLS) sandwich(bread, meat mat.Matrix) *mat.Dense {
+	k, _ := bread.Dims()
+	cov := mat.NewDense(k, k, nil)
+	cov.Product(bread, meat, bread)
+	return cov
 }
 
 // method to get the coefficients
@@ -228,11 +484,16 @@ func (ols *OLS) PValues() *mat.Dense {
 	return ols.pValues
 }
 
-// method to get the residuals
+// method to get the residuals, Y - X * beta
 func (ols *OLS) Residuals() *mat.Dense {
 	return ols.residuals
 }
 
+// method to get the whitened residuals, scaled by sqrt(weights) for WLS
+func (ols *OLS) WResiduals() *mat.Dense {
+	return ols.wResiduals
+}
+
 // method to get the fitted values
 func (ols *OLS) YHat() *mat.Dense {
 	return ols.yhat
@@ -259,7 +520,153 @@ func (ols *OLS) K() int {
 }
 
 // method to get the degrees of freedom
+// the rank of X replaces the number of columns once the model has run
 func (ols *OLS) DF() float64 {
-	return float64(ols.Y.RawMatrix().Rows - ols.X.RawMatrix().Cols)
+	if ols.coefficients != nil {
+		return float64(ols.N() - ols.rank)
+	}
+	return float64(ols.N() - ols.K())
+}
+
+// method to get (X'X)^-1, the covariance of the coefficients divided by sigma^2
+func (ols *OLS) NormalizedCovParams() *mat.Dense {
+	return ols.xTxInv
+}
+
+// method to get the variance of the residuals, SSR / DF
+func (ols *OLS) Scale() float64 {
+	return ols.sigma2
+}
+
+// method to get the condition number of X, the ratio of its extreme singular values
+// statsmodels reports the same number as Cond. No.
+func (ols *OLS) ConditionNumber() float64 {
+	return ols.condNumber
+}
+
+// method to get the numerical rank of X
+func (ols *OLS) Rank() int {
+	return ols.rank
+}
+
+// method to get the indexes of the aliased columns of X, empty at full rank
+func (ols *OLS) AliasedColumns() []int {
+	return ols.aliased
+}
+
+// method to get the covariance matrix of the coefficients
+func (ols *OLS) CovParams() *mat.Dense {
+	return ols.covParams
+}
+
+// method to get the covariance estimator
+func (ols *OLS) CovType() CovType {
+	return ols.covType
+}
+
+// method to get the number of lags used by the HAC estimator
+// AutoLags resolves to the Newey-West rule floor(4 * (n / 100)^(2/9))
+func (ols *OLS) Lags() int {
+	if ols.maxLags >= 0 {
+		return ols.maxLags
+	}
+	return int(math.Floor(4 * math.Pow(float64(ols.N())/100, 2.0/9.0)))
+}
+
+// method to get the name of the dependent variable, y by default
+func (ols *OLS) YName() string {
+	if ols.yName == "" {
+		return "y"
+	}
+	return ols.yName
+}
+
+// method to get the names of the independent variables, x1..xk by default
+func (ols *OLS) XNames() []string {
+	if ols.xNames != nil {
+		return ols.xNames
+	}
+	names := make([]string, ols.K())
+	for i := range names {
+		names[i] = "x" + strconv.Itoa(i+1)
+	}
+	return names
 }
 
+// method to get the confidence intervals of the coefficients at level 1 - alpha
+// one row per coefficient with the lower and the upper bound, using the
+// Student t with DF degrees of freedom, or the normal for a robust covariance
+func (ols *OLS) ConfInt(alpha float64) *mat.Dense {
+	q := ols.criticalValue(alpha)
+	k := ols.K()
+	ci := mat.NewDense(k, 2, nil)
+	for i := 0; i < k; i++ {
+		b := ols.coefficients.At(i, 0)
+		se := ols.standardErrors.At(i, 0)
+		ci.Set(i, 0, b-q*se)
+		ci.Set(i, 1, b+q*se)
+	}
+	return ci
+}
+
+// * method for the two sided critical value at level 1 - alpha
+// Student t with DF degrees of freedom, or the normal for a robust covariance
+func (ols *OLS) criticalValue(alpha float64) float64 {
+	if ols.covType == NonRobust {
+		return distuv.StudentsT{Mu: 0, Sigma: 1, Nu: ols.DF()}.Quantile(1 - alpha/2)
+	}
+	return distuv.UnitNormal.Quantile(1 - alpha/2)
+}
+
+// - define the forecasts of a fitted model at new observations
+type Prediction struct {
+	// Mean is the point forecast x'b
+	Mean []float64
+	// StdErrMean is the standard error of the mean, sqrt(x' V x) with V the coefficient covariance
+	StdErrMean []float64
+	// MeanLower and MeanUpper bound the confidence interval of the mean
+	MeanLower []float64
+	MeanUpper []float64
+	// ObsLower and ObsUpper bound the prediction interval of a new observation,
+	// whose variance adds the residual variance to the variance of the mean
+	ObsLower []float64
+	ObsUpper []float64
+}
+
+// method to forecast at the rows of newX, laid out as X, with intervals at the level (e.g. 0.95)
+// the intervals use the critical value of ConfInt, as get_prediction of statsmodels
+func (ols *OLS) Predict(newX *mat.Dense, level float64) (*Prediction, error) {
+	if ols.coefficients == nil {
+		return nil, errors.New("ols: Run the model before Predict")
+	}
+	if level <= 0 || level >= 1 {
+		return nil, errors.New("confidence level must be between 0 and 1")
+	}
+	m, k := newX.Dims()
+	if k != ols.K() {
+		return nil, &LengthMismatchError{What: "columns of newX", Expected: ols.K(), Got: k}
+	}
+	q := ols.criticalValue(1 - level)
+	pred := &Prediction{
+		Mean:       make([]float64, m),
+		StdErrMean: make([]float64, m),
+		MeanLower:  make([]float64, m),
+		MeanUpper:  make([]float64, m),
+		ObsLower:   make([]float64, m),
+		ObsUpper:   make([]float64, m),
+	}
+	for i := 0; i < m; i++ {
+		x := newX.RowView(i)
+		mean := mat.Dot(x, ols.coefficients.ColView(0))
+		variance := mat.Inner(x, ols.covParams, x)
+		se := math.Sqrt(variance)
+		seObs := math.Sqrt(variance + ols.sigma2)
+		pred.Mean[i] = mean
+		pred.StdErrMean[i] = se
+		pred.MeanLower[i] = mean - q*se
+		pred.MeanUpper[i] = mean + q*se
+		pred.ObsLower[i] = mean - q*seObs
+		pred.ObsUpper[i] = mean + q*seObs
+	}
+	return pred, nil
+}
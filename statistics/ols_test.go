@@ -1,9 +1,11 @@
 package statistics
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
 )
 
 // test ols ReadCSV
@@ -19,7 +21,7 @@ func TestReadCSV(t *testing.T) {
 
 	// Run the OLS model
 	ols := NewOLS(X, Y)
-	ols.Run()
+	assert.NoError(t, ols.Run())
 
 	// check if the Coefficients matrix is correct
 	Coefficients := ols.Coefficients()
@@ -55,4 +57,129 @@ func TestReadCSV(t *testing.T) {
 	// check if the adjRSquared is correct
 	adjRSquared := ols.AdjRSquared()
 	assert.InDelta(t, 0.887884407456738, adjRSquared, 0.0001)
-}
\ No newline at end of file
+}
+
+// test the robust covariance estimators
+// expected values of the statsmodels calls
+//
+//	OLS(index_price, add_constant(data[["interest_rate", "unemployment_rate"]])).fit(cov_type='HC1')
+//
+// and likewise for HC0, HC2, HC3 and HAC, from testdata/reference/robust.py
+// which follows them as statsmodels is not available to the test suite
+func TestRobustCovariance(t *testing.T) {
+	X, Y := ReadCSV("data.csv", true, "index_price", "interest_rate", "unemployment_rate")
+	expected := map[CovType][]float64{
+		HC0: {702.9746907928, 72.8721643889, 98.7477447150},
+		HC1: {751.5115555770, 77.9036206216, 105.5657795543},
+		HC2: {755.4203378537, 78.7056993896, 105.9654843310},
+		HC3: {812.2734472303, 85.1079855884, 113.7669613098},
+	}
+	for ct, se := range expected {
+		ols := NewOLS(X, Y, WithCovType(ct))
+		ols.Run()
+		assert.Equal(t, ct, ols.CovType())
+		for i, v := range se {
+			assert.InDelta(t, v, ols.StandardErrors().At(i, 0), 1e-6, ct.String())
+		}
+		// the coefficients do not depend on the covariance estimator
+		assert.InDelta(t, 345.5400870107158, ols.Coefficients().At(1, 0), 0.0001)
+	}
+	assert.Equal(t, "HC3", HC3.String())
+
+	// Newey-West with the automatic lag, floor(4 * (24 / 100)^(2/9)) = 2
+	ols := NewOLS(X, Y, WithCovType(HAC))
+	ols.Run()
+	assert.Equal(t, 2, ols.Lags())
+	assert.InDelta(t, 682.7192388556, ols.StandardErrors().At(0, 0), 1e-6)
+	assert.InDelta(t, 58.7489283312, ols.StandardErrors().At(1, 0), 1e-6)
+	assert.InDelta(t, 102.6646650709, ols.StandardErrors().At(2, 0), 1e-6)
+	assert.InDelta(t, 0.0084341267, ols.PValues().At(0, 0), 1e-8)
+	assert.InDelta(t, 0.0148285260, ols.PValues().At(2, 0), 1e-8)
+
+	// an explicit lag overrides the rule
+	ols = NewOLS(X, Y, WithCovType(HAC), WithMaxLags(0))
+	ols.Run()
+	assert.Equal(t, 0, ols.Lags())
+	assert.InDelta(t, 702.9746907928, ols.StandardErrors().At(0, 0), 1e-6)
+}
+
+// test the SVD solver on a full rank and a rank deficient design
+func TestOLSRank(t *testing.T) {
+	X, Y := ReadCSV("data.csv", true, "index_price", "interest_rate", "unemployment_rate")
+	ols := NewOLS(X, Y)
+	assert.NoError(t, ols.Run())
+	assert.Equal(t, 3, ols.Rank())
+	assert.Empty(t, ols.AliasedColumns())
+	// Cond. No. of the statsmodels summary, sqrt of the eigenvalue ratio of X'X
+	assert.InDelta(t, 394.32514338, ols.ConditionNumber(), 1e-6)
+	// sigma^2 * (X'X)^-1 is the classical covariance matrix
+	assert.InDelta(t, 899.248074996123*899.248074996123, ols.Scale()*ols.NormalizedCovParams().At(0, 0), 1e-4)
+
+	// append twice the interest rate, an exact copy up to the scale
+	n, _ := X.Dims()
+	Xd := mat.NewDense(n, 4, nil)
+	for i := 0; i < n; i++ {
+		Xd.Set(i, 0, X.At(i, 0))
+		Xd.Set(i, 1, X.At(i, 1))
+		Xd.Set(i, 2, X.At(i, 2))
+		Xd.Set(i, 3, 2*X.At(i, 1))
+	}
+	ols = NewOLS(Xd, Y)
+	err := ols.Run()
+	var rde *RankDeficientError
+	assert.True(t, errors.As(err, &rde))
+	assert.Equal(t, 3, rde.Rank)
+	assert.Equal(t, 4, rde.Columns)
+	assert.Equal(t, []int{3}, rde.Aliased)
+	assert.Equal(t, []int{3}, ols.AliasedColumns())
+	assert.True(t, ols.ConditionNumber() > 1e12)
+	// the minimum norm solution splits the interest rate effect 1:2
+	assert.InDelta(t, 345.5400870107158/5, ols.Coefficients().At(1, 0), 1e-6)
+	assert.InDelta(t, 2*345.5400870107158/5, ols.Coefficients().At(3, 0), 1e-6)
+	assert.InDelta(t, -250.14657136921616, ols.Coefficients().At(2, 0), 1e-6)
+	// the fit and the degrees of freedom are those of the full rank model
+	assert.InDelta(t, 0.8976335894170216, ols.RSquared(), 1e-9)
+	assert.Equal(t, 21.0, ols.DF())
+}
+
+// test the forecasts with their intervals
+// expected values of the statsmodels call
+//
+//	OLS(y, X).fit().get_prediction(newX).summary_frame(alpha=0.05)
+//
+// from testdata/reference/predict.py as statsmodels is not available to the test suite
+func TestOLSPredict(t *testing.T) {
+	X, Y := ReadCSV("data.csv", true, "index_price", "interest_rate", "unemployment_rate")
+	ols := NewOLS(X, Y)
+	_, err := ols.Predict(X, 0.95)
+	assert.Error(t, err)
+	assert.NoError(t, ols.Run())
+
+	newX := mat.NewDense(2, 3, []float64{
+		1, 2.5, 5.5,
+		1, 1.75, 6.1,
+	})
+	pred, err := ols.Predict(newX, 0.95)
+	assert.NoError(t, err)
+	expected := [][]float64{
+		{1286.44805262, 25.79107855, 1232.81256860, 1340.08353664, 1130.21154676, 1442.68455848},
+		{877.20504454, 20.43582685, 834.70641610, 919.70367298, 724.43327347, 1029.97681561},
+	}
+	for i, e := range expected {
+		assert.InDelta(t, e[0], pred.Mean[i], 1e-6)
+		assert.InDelta(t, e[1], pred.StdErrMean[i], 1e-6)
+		assert.InDelta(t, e[2], pred.MeanLower[i], 1e-6)
+		assert.InDelta(t, e[3], pred.MeanUpper[i], 1e-6)
+		assert.InDelta(t, e[4], pred.ObsLower[i], 1e-6)
+		assert.InDelta(t, e[5], pred.ObsUpper[i], 1e-6)
+	}
+
+	// the in sample forecasts are the fitted values
+	pred, err = ols.Predict(X, 0.9)
+	assert.NoError(t, err)
+	assert.InDelta(t, ols.YHat().At(5, 0), pred.Mean[5], 1e-9)
+
+	var lme *LengthMismatchError
+	_, err = ols.Predict(mat.NewDense(1, 2, nil), 0.95)
+	assert.ErrorAs(t, err, &lme)
+}
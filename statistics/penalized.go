@@ -0,0 +1,490 @@
+package statistics
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// - define a penalised linear regression with an unpenalised intercept
+// the objective is the one of glmnet and scikit-learn
+//
+//	1/(2n) ||y - b0 - X b||^2 + lambda * (l1Ratio ||b||_1 + (1 - l1Ratio) / 2 ||b||^2)
+//
+// l1Ratio 0 is ridge (solved in closed form), 1 is lasso, in between the elastic net
+// (both solved by cyclical coordinate descent)
+// X holds the regressors without a column of ones
+type PenalizedRegression struct {
+	X *mat.Dense
+	Y *mat.Dense
+
+	l1Ratio     float64
+	standardize bool
+	maxIter     int
+	tol         float64
+
+	lambda       float64
+	intercept    float64
+	coefficients []float64
+	iterations   int
+}
+
+type OptionPenalized func(*PenalizedRegression)
+
+// * for the standardisation of the regressors before the fit, true by default
+// the coefficients are always reported on the original scale
+func WithStandardize(standardize bool) OptionPenalized {
+	return func(pr *PenalizedRegression) {
+		pr.standardize = standardize
+	}
+}
+
+// * for the maximum number of coordinate descent sweeps, 1000 by default
+func WithMaxIter(maxIter int) OptionPenalized {
+	return func(pr *PenalizedRegression) {
+		pr.maxIter = maxIter
+	}
+}
+
+// * for the convergence tolerance on the largest coefficient change, 1e-7 by default
+func WithTolerance(tol float64) OptionPenalized {
+	return func(pr *PenalizedRegression) {
+		pr.tol = tol
+	}
+}
+
+// NewElasticNet creates a new elastic net regression with the given l1Ratio in [0, 1]
+func NewElasticNet(X, Y *mat.Dense, l1Ratio float64, opts ...OptionPenalized) *PenalizedRegression {
+	pr := &PenalizedRegression{
+		X:           X,
+		Y:           Y,
+		l1Ratio:     l1Ratio,
+		standardize: true,
+		maxIter:     1000,
+		tol:         1e-7,
+	}
+	for _, opt := range opts {
+		opt(pr)
+	}
+	return pr
+}
+
+// NewRidge creates a new ridge regression
+func NewRidge(X, Y *mat.Dense, opts ...OptionPenalized) *PenalizedRegression {
+	return NewElasticNet(X, Y, 0, opts...)
+}
+
+// NewLasso creates a new lasso regression
+func NewLasso(X, Y *mat.Dense, opts ...OptionPenalized) *PenalizedRegression {
+	return NewElasticNet(X, Y, 1, opts...)
+}
+
+// method Fit estimates the coefficients at the penalty lambda
+// when the coordinate descent reaches WithMaxIter sweeps before the tolerance it
+// returns a *ConvergenceError and the model holds the last sweep
+func (pr *PenalizedRegression) Fit(lambda float64) error {
+	if err := pr.check(lambda); err != nil {
+		return err
+	}
+	n, _ := pr.X.Dims()
+	rows := make([]int, n)
+	for i := range rows {
+		rows[i] = i
+	}
+	intercept, coefficients, _, sweeps, err := pr.solve(rows, lambda, nil)
+	if coefficients == nil {
+		return err
+	}
+	pr.lambda = lambda
+	pr.intercept, pr.coefficients, pr.iterations = intercept, coefficients, sweeps
+	return err
+}
+
+// method Path fits every lambda in turn, each fit starting from the previous one,
+// and returns the coefficients of each fit; the model is left at the last lambda
+// pass a decreasing path such as LambdaPath for the warm starts to pay off
+// it stops at the first fit that fails or does not converge
+func (pr *PenalizedRegression) Path(lambdas []float64) ([][]float64, error) {
+	n, _ := pr.X.Dims()
+	rows := make([]int, n)
+	for i := range rows {
+		rows[i] = i
+	}
+	path := make([][]float64, len(lambdas))
+	var warm []float64
+	for i, lambda := range lambdas {
+		if err := pr.check(lambda); err != nil {
+			return nil, err
+		}
+		intercept, coefficients, scaled, sweeps, err := pr.solve(rows, lambda, warm)
+		if coefficients != nil {
+			pr.lambda = lambda
+			pr.intercept, pr.coefficients, pr.iterations = intercept, coefficients, sweeps
+		}
+		if err != nil {
+			return nil, err
+		}
+		warm = scaled
+		path[i] = append([]float64(nil), pr.coefficients...)
+	}
+	return path, nil
+}
+
+// method LambdaPath gives nLambda penalties decreasing geometrically from the
+// smallest lambda that sets every lasso coefficient to zero down to ratio times it
+// as glmnet, the l1Ratio is floored at 1e-3 so that ridge gets a finite start
+func (pr *PenalizedRegression) LambdaPath(nLambda int, ratio float64) []float64 {
+	n, k := pr.X.Dims()
+	rows := make([]int, n)
+	for i := range rows {
+		rows[i] = i
+	}
+	cols, _, _, y, _ := pr.prepare(rows)
+	maxCorr := 0.0
+	for j := 0; j < k; j++ {
+		maxCorr = math.Max(maxCorr, math.Abs(floats.Dot(cols[j], y))/float64(n))
+	}
+	lambdaMax := maxCorr / math.Max(pr.l1Ratio, 1e-3)
+	path := make([]float64, nLambda)
+	for i := range path {
+		if nLambda == 1 {
+			path[i] = lambdaMax
+			continue
+		}
+		path[i] = lambdaMax * math.Pow(ratio, float64(i)/float64(nLambda-1))
+	}
+	return path
+}
+
+// method to get the slope coefficients on the original scale
+func (pr *PenalizedRegression) Coefficients() []float64 {
+	return pr.coefficients
+}
+
+// method to get the intercept
+func (pr *PenalizedRegression) Intercept() float64 {
+	return pr.intercept
+}
+
+// method to get the penalty of the last fit
+func (pr *PenalizedRegression) Lambda() float64 {
+	return pr.lambda
+}
+
+// method to get the number of coordinate descent sweeps of the last fit, 0 for ridge
+func (pr *PenalizedRegression) Iterations() int {
+	return pr.iterations
+}
+
+// method to get the indexes of the selected (non zero) coefficients
+func (pr *PenalizedRegression) NonZero() []int {
+	selected := make([]int, 0)
+	for j, b := range pr.coefficients {
+		if b != 0 {
+			selected = append(selected, j)
+		}
+	}
+	return selected
+}
+
+// method to forecast at the rows of newX, laid out as X
+func (pr *PenalizedRegression) Predict(newX *mat.Dense) ([]float64, error) {
+	if pr.coefficients == nil {
+		return nil, errors.New("penalized: Fit the model before Predict")
+	}
+	m, k := newX.Dims()
+	if k != len(pr.coefficients) {
+		return nil, &LengthMismatchError{What: "columns of newX", Expected: len(pr.coefficients), Got: k}
+	}
+	pred := make([]float64, m)
+	for i := range pred {
+		pred[i] = pr.intercept
+		for j, b := range pr.coefficients {
+			pred[i] += newX.At(i, j) * b
+		}
+	}
+	return pred, nil
+}
+
+// - define the cross-validation schemes
+type CVScheme int
+
+const (
+	// KFold splits the rows into k contiguous folds, each one held out in turn
+	KFold CVScheme = iota
+	// TimeSeriesSplit splits the rows into k + 1 contiguous blocks and tests
+	// each block from the second one on a model fitted on all the earlier blocks,
+	// so no fold ever looks into the future
+	TimeSeriesSplit
+)
+
+// - define the outcome of a cross-validation over a lambda path
+type CVResult struct {
+	Lambdas []float64
+	// MSE is the mean over the folds of the out of sample mean squared error
+	MSE []float64
+	// StdErr is the standard error of MSE across the folds
+	StdErr []float64
+	// Best is the lambda with the smallest MSE
+	Best float64
+	// Best1SE is the largest lambda whose MSE is within one standard error of the smallest
+	Best1SE float64
+	// NonConverged is the number of folds whose fit at each lambda reached
+	// WithMaxIter sweeps, those folds are scored with their last sweep
+	NonConverged []int
+}
+
+// method CrossValidate scores every lambda on k folds and refits the model at Best
+// a fold that does not converge is scored with its last sweep and counted in
+// NonConverged; when the refit at Best does not converge the result comes with
+// its *ConvergenceError
+func (pr *PenalizedRegression) CrossValidate(lambdas []float64, k int, scheme CVScheme) (*CVResult, error) {
+	n, _ := pr.X.Dims()
+	if len(lambdas) == 0 {
+		return nil, errors.New("penalized: no lambda to cross-validate")
+	}
+	for _, lambda := range lambdas {
+		if err := pr.check(lambda); err != nil {
+			return nil, err
+		}
+	}
+	blocks := k
+	if scheme == TimeSeriesSplit {
+		blocks = k + 1
+	}
+	if k < 2 && scheme == KFold || k < 1 || n < 2*blocks {
+		return nil, errors.New("penalized: not enough observations for the folds")
+	}
+	// the block b holds the rows [bounds[b], bounds[b+1])
+	bounds := make([]int, blocks+1)
+	for b := range bounds {
+		bounds[b] = b * n / blocks
+	}
+
+	errs := make([][]float64, len(lambdas))
+	nonConverged := make([]int, len(lambdas))
+	for i := range errs {
+		errs[i] = make([]float64, 0, k)
+	}
+	for f := 0; f < k; f++ {
+		test := f
+		if scheme == TimeSeriesSplit {
+			test = f + 1
+		}
+		train := make([]int, 0, n)
+		for i := 0; i < n; i++ {
+			if i >= bounds[test] && i < bounds[test+1] {
+				continue
+			}
+			if scheme == TimeSeriesSplit && i >= bounds[test] {
+				continue
+			}
+			train = append(train, i)
+		}
+		var warm []float64
+		for li, lambda := range lambdas {
+			b0, b, scaled, _, err := pr.solve(train, lambda, warm)
+			if b == nil {
+				return nil, err
+			}
+			if err != nil {
+				nonConverged[li]++
+			}
+			sse := 0.0
+			for i := bounds[test]; i < bounds[test+1]; i++ {
+				e := pr.Y.At(i, 0) - b0
+				for j, bj := range b {
+					e -= pr.X.At(i, j) * bj
+				}
+				sse += e * e
+			}
+			errs[li] = append(errs[li], sse/float64(bounds[test+1]-bounds[test]))
+			warm = scaled
+		}
+	}
+
+	cv := &CVResult{
+		Lambdas:      lambdas,
+		MSE:          make([]float64, len(lambdas)),
+		StdErr:       make([]float64, len(lambdas)),
+		NonConverged: nonConverged,
+	}
+	best := 0
+	for i := range lambdas {
+		cv.MSE[i] = stat.Mean(errs[i], nil)
+		cv.StdErr[i] = stat.StdDev(errs[i], nil) / math.Sqrt(float64(k))
+		if cv.MSE[i] < cv.MSE[best] {
+			best = i
+		}
+	}
+	cv.Best = lambdas[best]
+	cv.Best1SE = cv.Best
+	for i, lambda := range lambdas {
+		if cv.MSE[i] <= cv.MSE[best]+cv.StdErr[best] && lambda > cv.Best1SE {
+			cv.Best1SE = lambda
+		}
+	}
+	if err := pr.Fit(cv.Best); err != nil {
+		var ce *ConvergenceError
+		if errors.As(err, &ce) {
+			return cv, err
+		}
+		return nil, err
+	}
+	return cv, nil
+}
+
+// * method to validate a penalty and the inputs
+func (pr *PenalizedRegression) check(lambda float64) error {
+	n, _ := pr.X.Dims()
+	if r, _ := pr.Y.Dims(); r != n {
+		return &LengthMismatchError{What: "rows of Y", Expected: n, Got: r}
+	}
+	if pr.l1Ratio < 0 || pr.l1Ratio > 1 {
+		return errors.New("penalized: l1Ratio must be between 0 and 1")
+	}
+	if lambda < 0 {
+		return errors.New("penalized: lambda must not be negative")
+	}
+	return nil
+}
+
+// * method to center (and scale) the regressors and center y on the given rows
+// cols[j] is the prepared column j, mean and scale undo the transformation
+func (pr *PenalizedRegression) prepare(rows []int) (cols [][]float64, mean, scale []float64, y []float64, yMean float64) {
+	_, k := pr.X.Dims()
+	n := float64(len(rows))
+	cols = make([][]float64, k)
+	mean = make([]float64, k)
+	scale = make([]float64, k)
+	for j := 0; j < k; j++ {
+		col := make([]float64, len(rows))
+		for i, r := range rows {
+			col[i] = pr.X.At(r, j)
+		}
+		mean[j] = stat.Mean(col, nil)
+		ss := 0.0
+		for i := range col {
+			col[i] -= mean[j]
+			ss += col[i] * col[i]
+		}
+		scale[j] = 1
+		if pr.standardize && ss > 0 {
+			// the population standard deviation, as glmnet
+			scale[j] = math.Sqrt(ss / n)
+			for i := range col {
+				col[i] /= scale[j]
+			}
+		}
+		cols[j] = col
+	}
+	y = make([]float64, len(rows))
+	for i, r := range rows {
+		y[i] = pr.Y.At(r, 0)
+	}
+	yMean = stat.Mean(y, nil)
+	for i := range y {
+		y[i] -= yMean
+	}
+	return cols, mean, scale, y, yMean
+}
+
+// * method to fit on the given rows, warm are starting coefficients on the prepared scale
+// it returns the intercept, the coefficients on the original and on the prepared
+// scale (the next warm start) and the number of sweeps; the coefficients are nil
+// when the ridge system cannot be solved, and kept with a *ConvergenceError when
+// the sweeps reach maxIter
+func (pr *PenalizedRegression) solve(rows []int, lambda float64, warm []float64) (float64, []float64, []float64, int, error) {
+	cols, mean, scale, y, yMean := pr.prepare(rows)
+	k := len(cols)
+	n := float64(len(rows))
+	b := make([]float64, k)
+	sweeps := 0
+	var err error
+	if pr.l1Ratio == 0 {
+		// ridge: (X'X / n + lambda I) b = X'y / n
+		a := mat.NewSymDense(k, nil)
+		rhs := mat.NewVecDense(k, nil)
+		for i := 0; i < k; i++ {
+			for j := i; j < k; j++ {
+				v := floats.Dot(cols[i], cols[j]) / n
+				if i == j {
+					v += lambda
+				}
+				a.SetSym(i, j, v)
+			}
+			rhs.SetVec(i, floats.Dot(cols[i], y)/n)
+		}
+		var sol mat.VecDense
+		if err := sol.SolveVec(a, rhs); err != nil {
+			return 0, nil, nil, 0, err
+		}
+		for j := range b {
+			b[j] = sol.AtVec(j)
+		}
+	} else {
+		if len(warm) == k {
+			copy(b, warm)
+		}
+		// the residuals of the current coefficients
+		r := append([]float64(nil), y...)
+		for j := 0; j < k; j++ {
+			if b[j] != 0 {
+				floats.AddScaled(r, -b[j], cols[j])
+			}
+		}
+		sq := make([]float64, k)
+		for j := range cols {
+			sq[j] = floats.Dot(cols[j], cols[j]) / n
+		}
+		l1 := lambda * pr.l1Ratio
+		l2 := lambda * (1 - pr.l1Ratio)
+		converged := false
+		for sweeps = 1; sweeps <= pr.maxIter; sweeps++ {
+			maxChange := 0.0
+			for j := 0; j < k; j++ {
+				if sq[j] == 0 {
+					continue
+				}
+				rho := floats.Dot(cols[j], r)/n + sq[j]*b[j]
+				next := softThreshold(rho, l1) / (sq[j] + l2)
+				if change := next - b[j]; change != 0 {
+					floats.AddScaled(r, -change, cols[j])
+					maxChange = math.Max(maxChange, math.Abs(change))
+					b[j] = next
+				}
+			}
+			if maxChange < pr.tol {
+				converged = true
+				break
+			}
+		}
+		if !converged {
+			sweeps = pr.maxIter
+			err = &ConvergenceError{What: "coordinate descent", Iterations: pr.maxIter}
+		}
+	}
+	// back to the original scale
+	coefficients := make([]float64, k)
+	intercept := yMean
+	for j := range b {
+		coefficients[j] = b[j] / scale[j]
+		intercept -= coefficients[j] * mean[j]
+	}
+	return intercept, coefficients, b, sweeps, err
+}
+
+// * function for the soft thresholding operator sign(z) * max(|z| - g, 0)
+func softThreshold(z, g float64) float64 {
+	switch {
+	case z > g:
+		return z - g
+	case z < -g:
+		return z + g
+	default:
+		return 0
+	}
+}
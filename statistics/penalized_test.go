@@ -0,0 +1,153 @@
+package statistics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+// * helper to regress HAM1 on five other columns of managers.csv
+func penalizedData(t *testing.T) (*mat.Dense, *mat.Dense) {
+	rf, err := ReadFrame("../data/managers.csv")
+	assert.NoError(t, err)
+	rf, err = rf.Select("HAM1", "HAM3", "HAM4", "SP500 TR", "US 10Y TR", "US 3m TR")
+	assert.NoError(t, err)
+	rf = rf.DropNA()
+	n := rf.Len()
+	X := mat.NewDense(n, 5, nil)
+	Y := mat.NewDense(n, 1, rf.Columns[0])
+	for j := 0; j < 5; j++ {
+		X.SetCol(j, rf.Columns[j+1])
+	}
+	return X, Y
+}
+
+// test ridge, lasso and elastic net
+// expected values of the calls, on the regressors scaled by their population
+// standard deviation and back to the original scale
+//
+//	glmnet(X, y, alpha = 1, lambda = 0.002, thresh = 1e-14)
+//	Ridge(alpha=132 * 0.001).fit(Z, y)
+//	ElasticNet(alpha=0.002, l1_ratio=0.5, tol=1e-14).fit(Z, y)
+//
+// from testdata/reference/penalized.py as neither R nor scikit-learn is
+// available to the test suite; glmnet also rescales y for alpha < 1
+func TestPenalizedRegression(t *testing.T) {
+	X, Y := penalizedData(t)
+	n, _ := X.Dims()
+	assert.Equal(t, 132, n)
+
+	// ridge without penalty is OLS
+	ridge := NewRidge(X, Y)
+	assert.NoError(t, ridge.Fit(0))
+	ones := mat.NewDense(n, 6, nil)
+	for i := 0; i < n; i++ {
+		ones.Set(i, 0, 1)
+		for j := 0; j < 5; j++ {
+			ones.Set(i, j+1, X.At(i, j))
+		}
+	}
+	ols := NewOLS(ones, Y)
+	assert.NoError(t, ols.Run())
+	assert.InDelta(t, ols.Coefficients().At(0, 0), ridge.Intercept(), 1e-10)
+	for j := 0; j < 5; j++ {
+		assert.InDelta(t, ols.Coefficients().At(j+1, 0), ridge.Coefficients()[j], 1e-10)
+	}
+
+	assert.NoError(t, ridge.Fit(0.001))
+	assert.InDelta(t, 0.005583603593, ridge.Intercept(), 1e-10)
+	for j, v := range []float64{-0.036111725153, 0.138617950218, 0.297706796302, -0.203279312683, 0.859535371216} {
+		assert.InDelta(t, v, ridge.Coefficients()[j], 1e-10)
+	}
+
+	lasso := NewLasso(X, Y, WithTolerance(1e-12))
+	assert.NoError(t, lasso.Fit(0.002))
+	assert.InDelta(t, 0.008186022105, lasso.Intercept(), 1e-9)
+	for j, v := range []float64{0, 0.112317205135, 0.257613719214, -0.121530558819, 0} {
+		assert.InDelta(t, v, lasso.Coefficients()[j], 1e-9)
+	}
+	assert.Equal(t, []int{1, 2, 3}, lasso.NonZero())
+
+	enet := NewElasticNet(X, Y, 0.5, WithTolerance(1e-12))
+	assert.NoError(t, enet.Fit(0.002))
+	assert.InDelta(t, 0.008137731459, enet.Intercept(), 1e-9)
+	for j, v := range []float64{0, 0.122689758946, 0.270298176797, -0.161639393417, 0} {
+		assert.InDelta(t, v, enet.Coefficients()[j], 1e-9)
+	}
+
+	// the path starts where every lasso coefficient is zero
+	lambdas := lasso.LambdaPath(20, 0.01)
+	assert.InDelta(t, 0.016852533405, lambdas[0], 1e-10)
+	assert.InDelta(t, 0.016852533405*0.01, lambdas[19], 1e-12)
+	path, err := lasso.Path(lambdas)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 0, 0, 0, 0}, path[0])
+	assert.True(t, len(lasso.NonZero()) > 3)
+
+	pred, err := lasso.Predict(X.Slice(0, 2, 0, 5).(*mat.Dense))
+	assert.NoError(t, err)
+	assert.Len(t, pred, 2)
+
+	assert.Error(t, lasso.Fit(-1))
+	assert.Error(t, NewElasticNet(X, Y, 2).Fit(0.1))
+
+	// the sweep cap is reported, the model keeps the last sweep
+	capped := NewLasso(X, Y, WithMaxIter(1), WithTolerance(1e-12))
+	err = capped.Fit(0.002)
+	var ce *ConvergenceError
+	assert.ErrorAs(t, err, &ce)
+	assert.Equal(t, 1, capped.Iterations())
+	assert.Len(t, capped.Coefficients(), 5)
+	_, err = capped.Path(lambdas)
+	assert.ErrorAs(t, err, &ce)
+
+	// an unpenalized ridge on duplicated columns has no solution
+	dup := mat.NewDense(n, 2, nil)
+	dup.SetCol(0, mat.Col(nil, 0, X))
+	dup.SetCol(1, mat.Col(nil, 0, X))
+	singular := NewRidge(dup, Y)
+	assert.Error(t, singular.Fit(0))
+	assert.Nil(t, singular.Coefficients())
+}
+
+// test the cross-validation of the penalty
+func TestPenalizedCrossValidate(t *testing.T) {
+	X, Y := penalizedData(t)
+	lasso := NewLasso(X, Y)
+	lambdas := lasso.LambdaPath(30, 0.001)
+	for _, scheme := range []CVScheme{KFold, TimeSeriesSplit} {
+		cv, err := lasso.CrossValidate(lambdas, 5, scheme)
+		assert.NoError(t, err)
+		assert.Len(t, cv.MSE, 30)
+		assert.Contains(t, lambdas, cv.Best)
+		assert.True(t, cv.Best1SE >= cv.Best)
+		// the model is refitted at the best penalty
+		assert.Equal(t, cv.Best, lasso.Lambda())
+		assert.Equal(t, make([]int, 30), cv.NonConverged)
+		for i := range cv.MSE {
+			assert.True(t, cv.MSE[i] >= cv.MSE[indexOf(lambdas, cv.Best)])
+		}
+	}
+	_, err := lasso.CrossValidate(lambdas, 1, KFold)
+	assert.Error(t, err)
+
+	// folds at the sweep cap are scored with their last sweep and counted
+	capped := NewLasso(X, Y, WithMaxIter(2))
+	cv, err := capped.CrossValidate(lambdas, 5, KFold)
+	var ce *ConvergenceError
+	assert.ErrorAs(t, err, &ce)
+	assert.Len(t, cv.MSE, 30)
+	assert.Equal(t, cv.Best, capped.Lambda())
+	assert.Equal(t, 5, cv.NonConverged[29])
+}
+
+// * helper for the position of v in s
+func indexOf(s []float64, v float64) int {
+	for i := range s {
+		if s[i] == v {
+			return i
+		}
+	}
+	return -1
+}
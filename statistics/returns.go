@@ -47,22 +47,34 @@ func NewReturnsCalculator(opts ...OptionReturns) *ReturnsCalculator {
 // - Method for excess
 // take an interface{} as which can be a float64 or a []float64
 // when Rb is a float64 value, it means the risk-free rate after scaling
+// it returns nil when Rb has another type or length, use TryExcess for the reason
 func (rc *ReturnsCalculator) Excess(Rb interface{}) []float64 {
+	result, err := rc.TryExcess(Rb)
+	if err != nil {
+		return nil
+	}
+	return result
+}
 
+// - Method for excess returning a *TypeError or a *LengthMismatchError
+func (rc *ReturnsCalculator) TryExcess(Rb interface{}) ([]float64, error) {
 	result := make([]float64, len(rc.R))
 	switch v := Rb.(type) {
 	case float64:
 		for i := range rc.R {
 			result[i] = rc.R[i] - v
 		}
-		return result
+		return result, nil
 	case []float64:
+		if len(v) != len(rc.R) {
+			return nil, &LengthMismatchError{What: "Excess", Expected: len(rc.R), Got: len(v)}
+		}
 		for i := range rc.R {
 			result[i] = rc.R[i] - v[i]
 		}
-		return result
+		return result, nil
 	}
-	return nil
+	return nil, &TypeError{Value: Rb}
 }
 
 // - Method for cumulative
@@ -0,0 +1,410 @@
+package statistics
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// * struct for the layout of the moving windows
+type rollingSpec struct {
+	step      int
+	expanding bool
+	minObs    int
+	workers   int
+}
+
+type OptionRolling func(*rollingSpec)
+
+// * for the number of periods between two window ends, 1 by default
+func WithStep(step int) OptionRolling {
+	return func(rs *rollingSpec) {
+		rs.step = step
+	}
+}
+
+// * for an expanding window anchored at the first observation,
+// the window length is then the length of the first window
+func WithExpanding(expanding bool) OptionRolling {
+	return func(rs *rollingSpec) {
+		rs.expanding = expanding
+	}
+}
+
+// * for the minimum number of observations of a window, the window length by default
+// below the window length the first windows are the shorter ones anchored at the
+// first observation, as min_periods in pandas; Rolling also skips NaN values and
+// gives NaN when a window holds less than minObs valid values
+func WithMinObs(minObs int) OptionRolling {
+	return func(rs *rollingSpec) {
+		rs.minObs = minObs
+	}
+}
+
+// * for the number of goroutines evaluating the windows of Rolling, 1 by default
+// 0 or less uses GOMAXPROCS; the metric must then be safe for concurrent use
+// RollingRegression updates one set of cross products window after window and
+// returns an error for any other number than 1
+func WithWorkers(workers int) OptionRolling {
+	return func(rs *rollingSpec) {
+		rs.workers = workers
+	}
+}
+
+// * function to build the spec from the options
+func newRollingSpec(opts []OptionRolling) rollingSpec {
+	spec := rollingSpec{step: 1, workers: 1}
+	for _, opt := range opts {
+		opt(&spec)
+	}
+	if spec.workers <= 0 {
+		spec.workers = runtime.GOMAXPROCS(0)
+	}
+	return spec
+}
+
+// * method for the minimum number of observations of a window
+func (spec rollingSpec) minimum(window int) int {
+	if spec.minObs <= 0 || spec.minObs > window {
+		return window
+	}
+	return spec.minObs
+}
+
+// * method for the [start, end] rows of each window over n observations
+func (spec rollingSpec) windows(n, window int) [][2]int {
+	ws := make([][2]int, 0)
+	if spec.step < 1 {
+		return ws
+	}
+	for end := spec.minimum(window) - 1; end < n; end += spec.step {
+		start := end - window + 1
+		if spec.expanding || start < 0 {
+			start = 0
+		}
+		ws = append(ws, [2]int{start, end})
+	}
+	return ws
+}
+
+// - define the output of a rolling regression, one entry per window
+// a window whose X is rank deficient gets NaN estimates and a true RankDeficient
+type RollingRegressionResult struct {
+	// Dates are the dates of the last observation of each window
+	Dates []time.Time
+	// NObs is the number of observations of each window
+	NObs []int
+	// Coefficients and StdErrors hold one slice of K values per window
+	Coefficients [][]float64
+	StdErrors    [][]float64
+	RSquared     []float64
+	// RankDeficient flags the windows whose columns of X are linearly dependent
+	RankDeficient []bool
+	// Names label the columns of X in the series, x1..xk by default
+	Names []string
+
+	periodicity Periodicity
+}
+
+// - RollingRegression function
+// RollingRegression refits Y on X over a moving window of the given length
+// X'X and X'y are updated as observations enter and leave the window, and
+// rebuilt from scratch once every window length of removals to bound the
+// rounding drift, so each window costs O(k^2) instead of O(window * k^2)
+// the estimates are those of OLS with the nonrobust covariance; a window whose
+// X'X is too ill-conditioned for the normal equations is refitted through the
+// SVD of OLS, which also detects the rank deficient windows
+// WithStep, WithExpanding and WithMinObs apply, WithWorkers is an error
+func RollingRegression(dates []time.Time, X, Y *mat.Dense, window int, opts ...OptionRolling) (*RollingRegressionResult, error) {
+	n, k := X.Dims()
+	if r, _ := Y.Dims(); r != n {
+		return nil, &LengthMismatchError{What: "rows of Y", Expected: n, Got: r}
+	}
+	if len(dates) != n {
+		return nil, &LengthMismatchError{What: "dates", Expected: n, Got: len(dates)}
+	}
+	spec := newRollingSpec(opts)
+	if spec.minimum(window) <= k || window > n {
+		return nil, errors.New("rolling: the window must exceed the number of columns of X and fit in the sample")
+	}
+	if spec.step < 1 {
+		return nil, errors.New("rolling: the step must be positive")
+	}
+	if spec.workers != 1 {
+		return nil, errors.New("rolling: RollingRegression runs on a single goroutine, drop WithWorkers")
+	}
+	res := &RollingRegressionResult{
+		Names:       make([]string, k),
+		periodicity: InferPeriodicity(dates),
+	}
+	for j := range res.Names {
+		res.Names[j] = "x" + strconv.Itoa(j+1)
+	}
+
+	acc := newCrossProducts(X, Y)
+	start, end := 0, -1
+	removed := 0
+	for _, w := range spec.windows(n, window) {
+		if w[0] > end || removed+w[0]-start >= window {
+			// disjoint from the previous window or enough drift: rebuild
+			acc.reset()
+			start, end = w[0], w[0]-1
+			removed = 0
+		}
+		for ; end < w[1]; end++ {
+			acc.update(end+1, 1)
+		}
+		for ; start < w[0]; start++ {
+			acc.update(start, -1)
+			removed++
+		}
+		coef, se, r2, deficient := acc.solve(w[0], w[1])
+		res.Dates = append(res.Dates, dates[w[1]])
+		res.NObs = append(res.NObs, w[1]-w[0]+1)
+		res.Coefficients = append(res.Coefficients, coef)
+		res.StdErrors = append(res.StdErrors, se)
+		res.RSquared = append(res.RSquared, r2)
+		res.RankDeficient = append(res.RankDeficient, deficient)
+	}
+	return res, nil
+}
+
+// - Rolling function
+// Rolling applies fn, any metric of a return slice (SharpeRatio, MaxDrawdown,
+// StdDevAnnualized, Skewness, HurstIndex...), over a moving window of the given
+// length and returns its values dated at the end of each window, as apply.rolling
+// of PerformanceAnalytics
+// NaN values are left out of the windows, see WithMinObs; fn gets a fresh copy
+// of each window so it may reorder it
+func Rolling(dates []time.Time, values []float64, window int, fn func([]float64) float64, opts ...OptionRolling) (*ReturnSeries, error) {
+	n := len(values)
+	if len(dates) != n {
+		return nil, &LengthMismatchError{What: "dates", Expected: n, Got: len(dates)}
+	}
+	spec := newRollingSpec(opts)
+	if window < 1 || window > n {
+		return nil, errors.New("rolling: the window must be positive and fit in the sample")
+	}
+	if spec.step < 1 {
+		return nil, errors.New("rolling: the step must be positive")
+	}
+	ws := spec.windows(n, window)
+	minObs := spec.minimum(window)
+	out := make([]float64, len(ws))
+	eval := func(i int) {
+		w := ws[i]
+		buf := make([]float64, 0, w[1]-w[0]+1)
+		for _, v := range values[w[0] : w[1]+1] {
+			if !math.IsNaN(v) {
+				buf = append(buf, v)
+			}
+		}
+		if len(buf) < minObs {
+			out[i] = math.NaN()
+			return
+		}
+		out[i] = fn(buf)
+	}
+
+	if spec.workers == 1 {
+		for i := range ws {
+			eval(i)
+		}
+	} else {
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		for g := 0; g < spec.workers; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					eval(i)
+				}
+			}()
+		}
+		for i := range ws {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+	}
+
+	outDates := make([]time.Time, len(ws))
+	for i, w := range ws {
+		outDates[i] = dates[w[1]]
+	}
+	return &ReturnSeries{Dates: outDates, Values: out, Periodicity: InferPeriodicity(dates)}, nil
+}
+
+// - Method for a metric over a moving window, see Rolling
+// the result keeps the name and the periodicity of the series
+func (rs *ReturnSeries) Rolling(window int, fn func([]float64) float64, opts ...OptionRolling) (*ReturnSeries, error) {
+	out, err := Rolling(rs.Dates, rs.Values, window, fn, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out.Name = rs.Name
+	out.Periodicity = rs.Periodicity
+	return out, nil
+}
+
+// - Method for the series of the coefficient of column j
+func (res *RollingRegressionResult) Coefficient(j int) *ReturnSeries {
+	return res.series(res.Names[j], func(i int) float64 { return res.Coefficients[i][j] })
+}
+
+// - Method for the series of the standard error of column j
+func (res *RollingRegressionResult) StdError(j int) *ReturnSeries {
+	return res.series(res.Names[j]+" std err", func(i int) float64 { return res.StdErrors[i][j] })
+}
+
+// - Method for the series of the R-squared
+func (res *RollingRegressionResult) RSquaredSeries() *ReturnSeries {
+	return res.series("R-squared", func(i int) float64 { return res.RSquared[i] })
+}
+
+// * method to build a dated series from the windows
+func (res *RollingRegressionResult) series(name string, value func(i int) float64) *ReturnSeries {
+	values := make([]float64, len(res.Dates))
+	for i := range values {
+		values[i] = value(i)
+	}
+	return &ReturnSeries{Name: name, Dates: res.Dates, Values: values, Periodicity: res.periodicity}
+}
+
+// * struct for the running cross products of a regression window
+type crossProducts struct {
+	X, Y *mat.Dense
+
+	n    int
+	xTx  *mat.SymDense
+	xTy  *mat.VecDense
+	yTy  float64
+	sumY float64
+	chol mat.Cholesky
+}
+
+// * function to allocate the cross products once for all the windows
+func newCrossProducts(X, Y *mat.Dense) *crossProducts {
+	_, k := X.Dims()
+	return &crossProducts{
+		X:   X,
+		Y:   Y,
+		xTx: mat.NewSymDense(k, nil),
+		xTy: mat.NewVecDense(k, nil),
+	}
+}
+
+// * method to empty the window
+func (cp *crossProducts) reset() {
+	cp.n = 0
+	cp.xTx.Zero()
+	cp.xTy.Zero()
+	cp.yTy, cp.sumY = 0, 0
+}
+
+// * method to add (sign 1) or remove (sign -1) the observation of row i
+func (cp *crossProducts) update(i int, sign float64) {
+	_, k := cp.X.Dims()
+	y := cp.Y.At(i, 0)
+	for a := 0; a < k; a++ {
+		xa := cp.X.At(i, a)
+		for b := a; b < k; b++ {
+			cp.xTx.SetSym(a, b, cp.xTx.At(a, b)+sign*xa*cp.X.At(i, b))
+		}
+		cp.xTy.SetVec(a, cp.xTy.AtVec(a)+sign*xa*y)
+	}
+	cp.yTy += sign * y * y
+	cp.sumY += sign * y
+	cp.n += int(sign)
+}
+
+// the largest condition number of X'X solved by the normal equations, about
+// 1/sqrt(machineEpsilon): beyond it the squared conditioning of X'X leaves less
+// than half of the digits of the coefficients
+const choleskyCondLimit = 1e8
+
+// * method for the coefficients, the standard errors and the R-squared of the
+// window of rows start to end, and whether its X is rank deficient
+func (cp *crossProducts) solve(start, end int) (coef, se []float64, r2 float64, deficient bool) {
+	if !cp.chol.Factorize(cp.xTx) || cp.chol.Cond() > choleskyCondLimit {
+		return cp.solveSVD(start, end)
+	}
+	var b mat.VecDense
+	if err := cp.chol.SolveVecTo(&b, cp.xTy); err != nil {
+		return cp.solveSVD(start, end)
+	}
+	var inv mat.SymDense
+	if err := cp.chol.InverseTo(&inv); err != nil {
+		return cp.solveSVD(start, end)
+	}
+	k := cp.xTy.Len()
+	coef = make([]float64, k)
+	se = make([]float64, k)
+	// SSR = y'y - b'X'y, floored at zero against rounding
+	ssr := math.Max(cp.yTy-mat.Dot(&b, cp.xTy), 0)
+	sigma2 := ssr / float64(cp.n-k)
+	for j := 0; j < k; j++ {
+		coef[j] = b.AtVec(j)
+		se[j] = math.Sqrt(sigma2 * inv.At(j, j))
+	}
+	tss := cp.yTy - cp.sumY*cp.sumY/float64(cp.n)
+	return coef, se, 1 - ssr/tss, false
+}
+
+// * method to refit the window of rows start to end with OLS
+// the estimates are NaN when OLS reports an error, such as a rank deficient X
+func (cp *crossProducts) solveSVD(start, end int) (coef, se []float64, r2 float64, deficient bool) {
+	_, k := cp.X.Dims()
+	coef = make([]float64, k)
+	se = make([]float64, k)
+	ols := NewOLS(mat.DenseCopyOf(cp.X.Slice(start, end+1, 0, k)), mat.DenseCopyOf(cp.Y.Slice(start, end+1, 0, 1)))
+	if err := ols.Run(); err != nil {
+		var rde *RankDeficientError
+		for j := range coef {
+			coef[j], se[j] = math.NaN(), math.NaN()
+		}
+		return coef, se, math.NaN(), errors.As(err, &rde)
+	}
+	for j := 0; j < k; j++ {
+		coef[j] = ols.Coefficients().At(j, 0)
+		se[j] = ols.StandardErrors().At(j, 0)
+	}
+	return coef, se, ols.RSquared(), false
+}
+
+// - Method for the rolling CAPM beta and alpha against a benchmark series
+// the series are aligned on their common dates, then the return is regressed
+// on the benchmark over each window: the slope is CAPM.Beta and the alpha is
+// CAPM.Alpha for the per period risk free rate Rf, mean excess return minus
+// beta times the mean excess benchmark return
+func (rs *ReturnSeries) RollingCAPM(benchmark *ReturnSeries, Rf float64, window int, opts ...OptionRolling) (beta, alpha *ReturnSeries, err error) {
+	res, err := Align(rs, benchmark, InnerJoin, FillZero)
+	if err != nil {
+		return nil, nil, err
+	}
+	n := len(res.Ra.Values)
+	X := mat.NewDense(n, 2, nil)
+	for i, v := range res.Rb.Values {
+		X.Set(i, 0, 1)
+		X.Set(i, 1, v)
+	}
+	Y := mat.NewDense(n, 1, append([]float64(nil), res.Ra.Values...))
+	rr, err := RollingRegression(res.Ra.Dates, X, Y, window, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	rr.Names = []string{rs.Name + " alpha", rs.Name + " beta"}
+	beta = rr.Coefficient(1)
+	alpha = rr.Coefficient(0)
+	for i, b := range beta.Values {
+		// intercept = mean(Ra) - beta * mean(Rb), so the excess form only moves by Rf
+		alpha.Values[i] -= Rf * (1 - b)
+	}
+	return beta, alpha, nil
+}
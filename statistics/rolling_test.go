@@ -0,0 +1,168 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+// * helper for a regression of HAM2 on a constant and the market columns of managers.csv
+func rollingData(t *testing.T) ([]time.Time, *mat.Dense, *mat.Dense) {
+	rf, err := ReadFrame("../data/managers.csv")
+	assert.NoError(t, err)
+	rf, err = rf.Select("HAM2", "SP500 TR", "US 10Y TR", "US 3m TR")
+	assert.NoError(t, err)
+	rf = rf.DropNA()
+	n := rf.Len()
+	X := mat.NewDense(n, 4, nil)
+	Y := mat.NewDense(n, 1, rf.Columns[0])
+	for i := 0; i < n; i++ {
+		X.Set(i, 0, 1)
+	}
+	for j := 1; j < 4; j++ {
+		X.SetCol(j, rf.Columns[j])
+	}
+	return rf.Dates, X, Y
+}
+
+// test the rolling regression against a fresh OLS on every window
+func TestRollingRegression(t *testing.T) {
+	dates, X, Y := rollingData(t)
+	n, k := X.Dims()
+	assert.Equal(t, 125, n)
+
+	for _, opts := range [][]OptionRolling{nil, {WithStep(5)}, {WithExpanding(true), WithStep(7)}} {
+		rr, err := RollingRegression(dates, X, Y, 36, opts...)
+		assert.NoError(t, err)
+		spec := newRollingSpec(opts)
+		windows := spec.windows(n, 36)
+		assert.Equal(t, len(windows), len(rr.Dates))
+		for i, w := range windows {
+			ols := NewOLS(mat.DenseCopyOf(X.Slice(w[0], w[1]+1, 0, k)), mat.DenseCopyOf(Y.Slice(w[0], w[1]+1, 0, 1)))
+			assert.NoError(t, ols.Run())
+			assert.Equal(t, dates[w[1]], rr.Dates[i])
+			assert.Equal(t, w[1]-w[0]+1, rr.NObs[i])
+			for j := 0; j < k; j++ {
+				assert.InDelta(t, ols.Coefficients().At(j, 0), rr.Coefficients[i][j], 1e-8)
+				assert.InDelta(t, ols.StandardErrors().At(j, 0), rr.StdErrors[i][j], 1e-8)
+			}
+			assert.InDelta(t, ols.RSquared(), rr.RSquared[i], 1e-8)
+		}
+	}
+
+	rr, err := RollingRegression(dates, X, Y, 60)
+	assert.NoError(t, err)
+	coef := rr.Coefficient(2)
+	assert.Equal(t, "x3", coef.Name)
+	assert.Equal(t, n-59, coef.Len())
+	assert.Equal(t, Monthly, coef.Periodicity)
+	assert.Equal(t, rr.RSquared, rr.RSquaredSeries().Values)
+
+	_, err = RollingRegression(dates, X, Y, 4)
+	assert.Error(t, err)
+	_, err = RollingRegression(dates[1:], X, Y, 36)
+	assert.Error(t, err)
+	// the windows share one accumulator, workers are refused
+	_, err = RollingRegression(dates, X, Y, 36, WithWorkers(4))
+	assert.Error(t, err)
+	_, err = RollingRegression(dates, X, Y, 36, WithWorkers(1))
+	assert.NoError(t, err)
+
+	// the last column repeats the second one over the first 48 rows, exactly
+	// in Z and up to a tiny noise in W
+	Z := mat.DenseCopyOf(X)
+	W := mat.DenseCopyOf(X)
+	for i := 0; i < 48; i++ {
+		Z.Set(i, 3, X.At(i, 1))
+		W.Set(i, 3, X.At(i, 1)+1e-9*math.Sin(float64(i)))
+	}
+	rr, err = RollingRegression(dates, Z, Y, 36, WithStep(12))
+	assert.NoError(t, err)
+	// the windows end at rows 35, 47, 59..., the first two lie in the repeated rows
+	assert.Equal(t, []bool{true, true, false, false, false, false, false, false}, rr.RankDeficient)
+	assert.True(t, math.IsNaN(rr.Coefficients[0][1]))
+	assert.True(t, math.IsNaN(rr.RSquared[1]))
+	assert.False(t, math.IsNaN(rr.Coefficients[2][1]))
+
+	// the ill-conditioned window is refitted through the SVD of OLS
+	rr, err = RollingRegression(dates, W, Y, 36, WithStep(12))
+	assert.NoError(t, err)
+	assert.False(t, rr.RankDeficient[0])
+	ols := NewOLS(mat.DenseCopyOf(W.Slice(0, 36, 0, k)), mat.DenseCopyOf(Y.Slice(0, 36, 0, 1)))
+	assert.NoError(t, ols.Run())
+	for j := 0; j < k; j++ {
+		assert.InDelta(t, ols.Coefficients().At(j, 0), rr.Coefficients[0][j], 1e-6*math.Abs(ols.Coefficients().At(j, 0)))
+		assert.InDelta(t, ols.StandardErrors().At(j, 0), rr.StdErrors[0][j], 1e-6*ols.StandardErrors().At(j, 0))
+	}
+}
+
+// test the rolling CAPM beta and alpha against CAPM on each window
+func TestRollingCAPM(t *testing.T) {
+	rs, err := ReadSeries("../data/managers.csv", "HAM1")
+	assert.NoError(t, err)
+	bm, err := ReadSeries("../data/managers.csv", "SP500 TR")
+	assert.NoError(t, err)
+	rf := 0.035 / 12
+	beta, alpha, err := rs.RollingCAPM(bm, rf, 24)
+	assert.NoError(t, err)
+	assert.Equal(t, "HAM1 beta", beta.Name)
+	assert.Equal(t, rs.Len()-23, beta.Len())
+	for _, i := range []int{0, 17, beta.Len() - 1} {
+		lo := i
+		c := NewCAPM(WithRa(rs.Values[lo:lo+24]), WithRb(bm.Values[lo:lo+24]))
+		assert.Equal(t, rs.Dates[lo+23], beta.Dates[i])
+		assert.InDelta(t, c.Beta(), beta.Values[i], 1e-10)
+		assert.InDelta(t, c.Alpha(rf), alpha.Values[i], 1e-10)
+	}
+}
+
+// test the generic rolling engine
+func TestRolling(t *testing.T) {
+	rs, err := ReadSeries("../data/managers.csv", "HAM1")
+	assert.NoError(t, err)
+	sharpe := func(r []float64) float64 { return SharpeRatio(r, 0.0, 12, true) }
+
+	roll, err := rs.Rolling(36, sharpe)
+	assert.NoError(t, err)
+	assert.Equal(t, "HAM1", roll.Name)
+	assert.Equal(t, rs.Len()-35, roll.Len())
+	assert.Equal(t, rs.Dates[35], roll.Dates[0])
+	for _, i := range []int{0, 40, roll.Len() - 1} {
+		assert.InDelta(t, sharpe(rs.Values[i:i+36]), roll.Values[i], 1e-12)
+	}
+
+	// the parallel evaluation gives the same series
+	par, err := rs.Rolling(36, sharpe, WithWorkers(0))
+	assert.NoError(t, err)
+	assert.Equal(t, roll.Values, par.Values)
+
+	// step and expanding windows
+	exp, err := rs.Rolling(12, MaxDrawdown, WithExpanding(true), WithStep(12))
+	assert.NoError(t, err)
+	assert.Equal(t, 11, exp.Len())
+	assert.Equal(t, rs.Dates[23], exp.Dates[1])
+	assert.InDelta(t, MaxDrawdown(rs.Values[:24]), exp.Values[1], 1e-12)
+	assert.InDelta(t, MaxDrawdown(rs.Values), exp.Values[10], 1e-12)
+
+	// NaN values are skipped and short windows give NaN
+	rf, err := ReadFrame("../data/managers.csv")
+	assert.NoError(t, err)
+	ham2, err := rf.Column("HAM2")
+	assert.NoError(t, err)
+	sd, err := Rolling(rf.Dates, ham2, 12, StdDev, WithMinObs(6))
+	assert.NoError(t, err)
+	assert.Equal(t, rf.Len()-5, sd.Len())
+	assert.Equal(t, rf.Dates[5], sd.Dates[0])
+	// HAM2 starts with 7 missing months
+	assert.True(t, math.IsNaN(sd.Values[0]))
+	assert.InDelta(t, StdDev(ham2[7:13]), sd.Values[7], 1e-12)
+	assert.InDelta(t, StdDev(ham2[8:20]), sd.Values[14], 1e-12)
+
+	_, err = rs.Rolling(0, sharpe)
+	assert.Error(t, err)
+	_, err = rs.Rolling(12, sharpe, WithStep(0))
+	assert.Error(t, err)
+}
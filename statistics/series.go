@@ -0,0 +1,434 @@
+package statistics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DateLayout is the layout of the date column in the shipped csv files
+const DateLayout = "2006-01-02"
+
+// - define the periodicity of a return series
+// the value is the number of periods in a year, so it can be
+// passed directly as the scale argument of the metric functions
+type Periodicity int
+
+const (
+	Daily     Periodicity = 252
+	Weekly    Periodicity = 52
+	Monthly   Periodicity = 12
+	Quarterly Periodicity = 4
+	Yearly    Periodicity = 1
+)
+
+// Scale returns the number of periods in a year
+func (p Periodicity) Scale() int {
+	return int(p)
+}
+
+// String returns the name of the periodicity
+func (p Periodicity) String() string {
+	switch p {
+	case Daily:
+		return "daily"
+	case Weekly:
+		return "weekly"
+	case Monthly:
+		return "monthly"
+	case Quarterly:
+		return "quarterly"
+	case Yearly:
+		return "yearly"
+	default:
+		return strconv.Itoa(int(p)) + " periods per year"
+	}
+}
+
+// InferPeriodicity guesses the periodicity from the median gap between dates
+// it falls back to Monthly when there are less than two dates
+func InferPeriodicity(dates []time.Time) Periodicity {
+	if len(dates) < 2 {
+		return Monthly
+	}
+	gaps := make([]float64, len(dates)-1)
+	for i := 1; i < len(dates); i++ {
+		gaps[i-1] = dates[i].Sub(dates[i-1]).Hours() / 24
+	}
+	sort.Float64s(gaps)
+	days := gaps[len(gaps)/2]
+	switch {
+	case days <= 4:
+		return Daily
+	case days <= 10:
+		return Weekly
+	case days <= 45:
+		return Monthly
+	case days <= 135:
+		return Quarterly
+	default:
+		return Yearly
+	}
+}
+
+// - define a struct to hold a date indexed return series
+type ReturnSeries struct {
+	// Name is the name of the series, usually the csv column
+	Name string
+	// Dates is the time index, one date for each value
+	Dates []time.Time
+	// Values are the period returns
+	Values []float64
+	// Periodicity is the declared number of periods in a year
+	Periodicity Periodicity
+}
+
+type OptionSeries func(*ReturnSeries)
+
+// * for the series name
+func WithName(name string) OptionSeries {
+	return func(rs *ReturnSeries) {
+		rs.Name = name
+	}
+}
+
+// * for the declared periodicity
+func WithPeriodicity(p Periodicity) OptionSeries {
+	return func(rs *ReturnSeries) {
+		rs.Periodicity = p
+	}
+}
+
+// NewReturnSeries creates a new date indexed return series
+// the dates must be as many as the values and strictly increasing
+// when no periodicity is declared it is inferred from the dates
+func NewReturnSeries(dates []time.Time, values []float64, opts ...OptionSeries) (*ReturnSeries, error) {
+	if len(dates) != len(values) {
+		return nil, &LengthMismatchError{What: "dates and values", Expected: len(dates), Got: len(values)}
+	}
+	for i := 1; i < len(dates); i++ {
+		if !dates[i].After(dates[i-1]) {
+			return nil, errors.New("dates are not strictly increasing")
+		}
+	}
+	rs := &ReturnSeries{
+		Dates:  dates,
+		Values: values,
+	}
+	for _, opt := range opts {
+		opt(rs)
+	}
+	if rs.Periodicity == 0 {
+		rs.Periodicity = InferPeriodicity(dates)
+	}
+	return rs, nil
+}
+
+// SeriesFromData builds a return series from the output of ReadData
+// the first column holds the dates; rows with a blank or NA value in the
+// named column are skipped, any other unparsable value is a *ParseError
+func SeriesFromData(dt [][]string, fields []string, name string, opts ...OptionSeries) (*ReturnSeries, error) {
+	pos, err := CheckPos(fields, name)
+	if err != nil {
+		return nil, err
+	}
+	dates := make([]time.Time, 0, len(dt))
+	values := make([]float64, 0, len(dt))
+	for i, row := range dt {
+		v, e := parseCell(row[pos])
+		if e != nil {
+			return nil, &ParseError{Row: i + 1, Column: name, Value: row[pos], Err: e}
+		}
+		if math.IsNaN(v) {
+			continue
+		}
+		d, e := parseDate(row[0])
+		if e != nil {
+			return nil, &ParseError{Row: i + 1, Column: fields[0], Value: row[0], Err: e}
+		}
+		dates = append(dates, d)
+		values = append(values, v)
+	}
+	return NewReturnSeries(dates, values, append([]OptionSeries{WithName(name)}, opts...)...)
+}
+
+// ReadSeries reads one named column of a csv file such as managers.csv
+// missing values are dropped together with their dates
+func ReadSeries(path, name string, opts ...OptionSeries) (*ReturnSeries, error) {
+	rf, err := ReadFrame(path)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := rf.Series(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(rs)
+	}
+	return rs, nil
+}
+
+// - Method for the number of observations
+func (rs *ReturnSeries) Len() int {
+	return len(rs.Values)
+}
+
+// - Method for the first date
+func (rs *ReturnSeries) Start() time.Time {
+	if len(rs.Dates) == 0 {
+		return time.Time{}
+	}
+	return rs.Dates[0]
+}
+
+// - Method for the last date
+func (rs *ReturnSeries) End() time.Time {
+	if len(rs.Dates) == 0 {
+		return time.Time{}
+	}
+	return rs.Dates[len(rs.Dates)-1]
+}
+
+// - Method for a sub series between two dates, both included
+// a zero time means no bound on that side
+func (rs *ReturnSeries) Window(from, to time.Time) *ReturnSeries {
+	lo := 0
+	if !from.IsZero() {
+		lo = sort.Search(len(rs.Dates), func(i int) bool { return !rs.Dates[i].Before(from) })
+	}
+	hi := len(rs.Dates)
+	if !to.IsZero() {
+		hi = sort.Search(len(rs.Dates), func(i int) bool { return rs.Dates[i].After(to) })
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return &ReturnSeries{
+		Name:        rs.Name,
+		Dates:       rs.Dates[lo:hi],
+		Values:      rs.Values[lo:hi],
+		Periodicity: rs.Periodicity,
+	}
+}
+
+// - Method for the last n observations
+func (rs *ReturnSeries) Trailing(n int) *ReturnSeries {
+	lo := len(rs.Values) - n
+	if lo < 0 {
+		lo = 0
+	}
+	return &ReturnSeries{
+		Name:        rs.Name,
+		Dates:       rs.Dates[lo:],
+		Values:      rs.Values[lo:],
+		Periodicity: rs.Periodicity,
+	}
+}
+
+// - Method for the returns calculator of the series
+func (rs *ReturnSeries) Calculator() *ReturnsCalculator {
+	return NewReturnsCalculator(WithReturns(rs.Values))
+}
+
+// - Method for the annualized return
+func (rs *ReturnSeries) AnnualizedReturn(geometric bool) float64 {
+	return AnnualizedReturn(rs.Values, rs.Periodicity.Scale(), geometric)
+}
+
+// - Method for the annualized standard deviation
+func (rs *ReturnSeries) StdDevAnnualized() float64 {
+	return StdDevAnnualized(rs.Values, rs.Periodicity.Scale())
+}
+
+// - Method for the Sharpe ratio
+// Rf is a float64 per period rate or a []float64 with one rate per observation
+func (rs *ReturnSeries) SharpeRatio(Rf interface{}, geometric bool) float64 {
+	return SharpeRatio(rs.Values, Rf, rs.Periodicity.Scale(), geometric)
+}
+
+// - Method for the probabilistic Sharpe ratio against a per period benchmark Sharpe ratio
+func (rs *ReturnSeries) ProbabilisticSharpeRatio(Rf interface{}, benchmarkSR float64) float64 {
+	return ProbabilisticSharpeRatio(rs.Values, Rf, benchmarkSR)
+}
+
+// - Method for the deflated Sharpe ratio of the best of nTrials strategies
+func (rs *ReturnSeries) DeflatedSharpeRatio(Rf interface{}, nTrials int, trialsVariance float64) float64 {
+	return DeflatedSharpeRatio(rs.Values, Rf, nTrials, trialsVariance)
+}
+
+// - Method for the minimum track record length, in periods
+func (rs *ReturnSeries) MinTrackRecordLength(Rf interface{}, benchmarkSR, prob float64) float64 {
+	return MinTrackRecordLength(rs.Values, Rf, benchmarkSR, prob)
+}
+
+// - Method for the maximum drawdown
+func (rs *ReturnSeries) MaxDrawdown() float64 {
+	return MaxDrawdown(rs.Values)
+}
+
+// - Method for the skewness, see Skewness for the tags
+func (rs *ReturnSeries) Skewness(tag string) float64 {
+	return Skewness(rs.Values, tag)
+}
+
+// - Method for the kurtosis, see Kurtosis for the tags
+func (rs *ReturnSeries) Kurtosis(tag string) float64 {
+	return Kurtosis(rs.Values, tag)
+}
+
+// - Method for the CAPM against a benchmark series
+// the two series are aligned on their common dates first
+func (rs *ReturnSeries) CAPM(benchmark *ReturnSeries) (*CAPM, error) {
+	ra, rb, _, err := rs.alignWith(benchmark)
+	if err != nil {
+		return nil, err
+	}
+	return NewCAPM(WithRa(ra), WithRb(rb)), nil
+}
+
+// - Method for the market timing coefficients against a benchmark series
+// the two series are aligned on their common dates first, see MarketTiming for the tags
+// Rf is a float64 per period rate, a *ReturnSeries aligned on the same dates,
+// or a []float64 with one rate per observation of rs
+func (rs *ReturnSeries) MarketTiming(benchmark *ReturnSeries, Rf interface{}, tag string) (Alpha, Beta, Gamma float64, err error) {
+	ra, rb, rows, err := rs.alignWith(benchmark)
+	if err != nil {
+		return math.NaN(), math.NaN(), math.NaN(), err
+	}
+	rf, err := rs.alignRf(Rf, rows)
+	if err != nil {
+		return math.NaN(), math.NaN(), math.NaN(), err
+	}
+	Alpha, Beta, Gamma = MarketTiming(ra, rb, rf, tag)
+	return Alpha, Beta, Gamma, nil
+}
+
+// - Method for the active premium against a benchmark series
+func (rs *ReturnSeries) ActivePremium(benchmark *ReturnSeries, geometric bool) (float64, error) {
+	ra, rb, _, err := rs.alignWith(benchmark)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return ActivePremium(ra, rb, rs.Periodicity.Scale(), geometric), nil
+}
+
+// - Method for the tracking error against a benchmark series
+func (rs *ReturnSeries) TrackingError(benchmark *ReturnSeries) (float64, error) {
+	ra, rb, _, err := rs.alignWith(benchmark)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return TrackingError(ra, rb, rs.Periodicity.Scale()), nil
+}
+
+// - Method for the information ratio against a benchmark series
+func (rs *ReturnSeries) InformationRatio(benchmark *ReturnSeries) (float64, error) {
+	ra, rb, _, err := rs.alignWith(benchmark)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return InformationRatio(ra, rb, rs.Periodicity.Scale()), nil
+}
+
+// * method to get the values of both series on their common dates
+// rows are the positions of the common dates in rs
+func (rs *ReturnSeries) alignWith(benchmark *ReturnSeries) (ra, rb []float64, rows []int, err error) {
+	res, err := Align(rs, benchmark, InnerJoin, FillZero)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if res.Ra.Len() == 0 {
+		return nil, nil, nil, errors.New("the series have no common date")
+	}
+	rows = make([]int, 0, res.Ra.Len())
+	k := 0
+	for i, d := range rs.Dates {
+		if k < len(res.Ra.Dates) && d.Equal(res.Ra.Dates[k]) {
+			rows = append(rows, i)
+			k++
+		}
+	}
+	return res.Ra.Values, res.Rb.Values, rows, nil
+}
+
+// * method to restrict a risk free rate to the rows of rs kept by the alignment
+// a *ReturnSeries must have a value on every kept date
+func (rs *ReturnSeries) alignRf(Rf interface{}, rows []int) (interface{}, error) {
+	switch v := Rf.(type) {
+	case float64:
+		return v, nil
+	case []float64:
+		if len(v) != rs.Len() {
+			return nil, &LengthMismatchError{What: "Rf", Expected: rs.Len(), Got: len(v)}
+		}
+		out := make([]float64, len(rows))
+		for k, i := range rows {
+			out[k] = v[i]
+		}
+		return out, nil
+	case *ReturnSeries:
+		values := make(map[time.Time]float64, v.Len())
+		for i, d := range v.Dates {
+			values[d] = v.Values[i]
+		}
+		out := make([]float64, len(rows))
+		for k, i := range rows {
+			r, ok := values[rs.Dates[i]]
+			if !ok || math.IsNaN(r) {
+				return nil, fmt.Errorf("Rf has no value on %s", rs.Dates[i].Format(DateLayout))
+			}
+			out[k] = r
+		}
+		return out, nil
+	}
+	return nil, &TypeError{Value: Rf}
+}
+
+// - Method for the Value-at-Risk, see VaR for the tags
+func (rs *ReturnSeries) VaR(p float64, tag string) float64 {
+	return VaR(rs.Values, p, tag)
+}
+
+// - Method for the Expected Shortfall, see ES for the tags
+func (rs *ReturnSeries) ES(p float64, tag string) float64 {
+	return ES(rs.Values, p, tag)
+}
+
+// - Method for the Calmar ratio
+func (rs *ReturnSeries) CalmarRatio() float64 {
+	return CalmarRatio(rs.Values, rs.Periodicity.Scale())
+}
+
+// - Method for the Sterling ratio
+func (rs *ReturnSeries) SterlingRatio(excess float64) float64 {
+	return SterlingRatio(rs.Values, rs.Periodicity.Scale(), excess)
+}
+
+// - Method for the Burke ratio, Rf is an annual rate
+func (rs *ReturnSeries) BurkeRatio(Rf float64, modified bool) float64 {
+	return BurkeRatio(rs.Values, Rf, rs.Periodicity.Scale(), modified)
+}
+
+// - Method for the Martin ratio, Rf is an annual rate
+func (rs *ReturnSeries) MartinRatio(Rf float64) float64 {
+	return MartinRatio(rs.Values, Rf, rs.Periodicity.Scale())
+}
+
+// - Method for the Pain ratio, Rf is an annual rate
+func (rs *ReturnSeries) PainRatio(Rf float64) float64 {
+	return PainRatio(rs.Values, Rf, rs.Periodicity.Scale())
+}
+
+// - Method for the Sortino ratio, see SortinoRatio for MAR
+func (rs *ReturnSeries) SortinoRatio(MAR interface{}) float64 {
+	return SortinoRatio(rs.Values, MAR)
+}
+
+// - Method for the Omega ratio at a threshold
+func (rs *ReturnSeries) OmegaRatio(L float64) float64 {
+	return OmegaRatio(rs.Values, L)
+}
@@ -0,0 +1,82 @@
+package statistics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test ReturnSeries constructors and metric methods
+func TestReturnSeries(t *testing.T) {
+	rs, err := ReadSeries("../data/managers.csv", "HAM1")
+	assert.Nil(t, err)
+	assert.Equal(t, "HAM1", rs.Name)
+	assert.Equal(t, Monthly, rs.Periodicity)
+	assert.Equal(t, 132, rs.Len())
+	assert.Equal(t, time.Date(1996, 1, 31, 0, 0, 0, 0, time.UTC), rs.Start())
+	assert.Equal(t, time.Date(2006, 12, 31, 0, 0, 0, 0, time.UTC), rs.End())
+
+	// same numbers as the slice based tests
+	assert.InDelta(t, 0.1375320, rs.AnnualizedReturn(true), 0.0000001)
+	assert.InDelta(t, 0.0887808, rs.StdDevAnnualized(), 0.000001)
+	assert.InDelta(t, 0.3201889, rs.SharpeRatio(0.035/12, true), 0.000001)
+	assert.InDelta(t, 0.1517729, rs.MaxDrawdown(), 0.0000001)
+	assert.InDelta(t, -0.6588445, rs.Skewness("moment"), 0.0000001)
+
+	bm, err := ReadSeries("../data/managers.csv", "SP500 TR")
+	assert.Nil(t, err)
+	capm, err := rs.CAPM(bm)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.3906033, capm.Beta(), 0.0000001)
+
+	// HAM2 starts late and keeps its own dates
+	rs2, err := ReadSeries("../data/managers.csv", "HAM2")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(1996, 8, 31, 0, 0, 0, 0, time.UTC), rs2.Start())
+	assert.InDelta(t, 0.1746569, rs2.AnnualizedReturn(true), 0.0000001)
+
+	// trailing and calendar windows
+	last := rs.Trailing(12)
+	assert.Equal(t, 12, last.Len())
+	assert.Equal(t, time.Date(2006, 1, 31, 0, 0, 0, 0, time.UTC), last.Start())
+	year := rs.Window(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2000, 12, 31, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, 12, year.Len())
+	assert.Equal(t, time.Date(2000, 12, 31, 0, 0, 0, 0, time.UTC), year.End())
+
+	_, err = ReadSeries("../data/managers.csv", "HAM9")
+	assert.NotNil(t, err)
+	_, err = NewReturnSeries(rs.Dates[:2], rs.Values[:3])
+	assert.NotNil(t, err)
+}
+
+// Test the periodicity guess
+func TestInferPeriodicity(t *testing.T) {
+	start := time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)
+	days := []time.Time{start, start.AddDate(0, 0, 1), start.AddDate(0, 0, 2), start.AddDate(0, 0, 3)}
+	assert.Equal(t, Daily, InferPeriodicity(days))
+	weeks := []time.Time{start, start.AddDate(0, 0, 7), start.AddDate(0, 0, 14)}
+	assert.Equal(t, Weekly, InferPeriodicity(weeks))
+	quarters := []time.Time{start, start.AddDate(0, 3, 0), start.AddDate(0, 6, 0)}
+	assert.Equal(t, Quarterly, InferPeriodicity(quarters))
+	assert.Equal(t, 12, Monthly.Scale())
+}
+
+// Test the series built from raw csv rows
+func TestSeriesFromData(t *testing.T) {
+	fields := []string{"Date", "A"}
+	dt := [][]string{{"2020-01-31", "0.01"}, {"2020-02-29", ""}, {"2020-03-31", "NA"}, {"2020-04-30", "0.02"}}
+	rs, err := SeriesFromData(dt, fields, "A")
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.01, 0.02}, rs.Values)
+	assert.Equal(t, time.Date(2020, 4, 30, 0, 0, 0, 0, time.UTC), rs.End())
+
+	// a malformed cell is an error, not a missing value
+	dt[1][1] = "0.0x"
+	_, err = SeriesFromData(dt, fields, "A")
+	var pe *ParseError
+	assert.ErrorAs(t, err, &pe)
+	assert.Equal(t, 2, pe.Row)
+	assert.Equal(t, "A", pe.Column)
+	assert.Equal(t, "0.0x", pe.Value)
+}
@@ -0,0 +1,110 @@
+package statistics
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// the Euler-Mascheroni constant of the expected maximum of Gaussian trials
+const eulerGamma = 0.5772156649015329
+
+// - ProbabilisticSharpeRatio function
+// ProbabilisticSharpeRatio is the probability that the true Sharpe ratio of Ra
+// exceeds benchmarkSR, Bailey and Lopez de Prado (2012); SR and benchmarkSR are
+// per period, not annualized, and the moments are the "moment" Skewness and
+// Kurtosis of the excess returns, see ProbabilisticSharpeRatioFromMoments
+func ProbabilisticSharpeRatio(Ra []float64, Rf interface{}, benchmarkSR float64) float64 {
+	sr, skew, kurt, n := sharpeMoments(Ra, Rf)
+	return ProbabilisticSharpeRatioFromMoments(sr, benchmarkSR, skew, kurt, n)
+}
+
+// - ProbabilisticSharpeRatioFromMoments function
+// Phi((SR - SR*) * sqrt(n - 1) / sqrt(1 - skew * SR + (kurt - 1) / 4 * SR^2))
+// for a per period Sharpe ratio sr over n observations, kurt is not in excess
+// it is NaN when the variance term is not positive: the sample moments satisfy
+// kurt >= skew^2 + 1, so the term is at least (1 - skew * SR / 2)^2 and only
+// vanishes for a two-valued sample, or when sr is NaN (a constant sample)
+func ProbabilisticSharpeRatioFromMoments(sr, benchmarkSR, skew, kurt float64, n int) float64 {
+	sigma := sharpeDispersion(sr, skew, kurt)
+	if math.IsNaN(sigma) || n < 2 {
+		return math.NaN()
+	}
+	return distuv.UnitNormal.CDF((sr - benchmarkSR) * math.Sqrt(float64(n-1)) / sigma)
+}
+
+// - MinTrackRecordLength function
+// MinTrackRecordLength is the number of periods needed for the observed Sharpe
+// ratio to exceed benchmarkSR with probability prob (e.g. 0.95),
+// see MinTrackRecordLengthFromMoments
+func MinTrackRecordLength(Ra []float64, Rf interface{}, benchmarkSR, prob float64) float64 {
+	sr, skew, kurt, _ := sharpeMoments(Ra, Rf)
+	return MinTrackRecordLengthFromMoments(sr, benchmarkSR, skew, kurt, prob)
+}
+
+// - MinTrackRecordLengthFromMoments function
+// 1 + (1 - skew * SR + (kurt - 1) / 4 * SR^2) * (z_prob / (SR - SR*))^2
+// it is +Inf when the Sharpe ratio does not exceed benchmarkSR and NaN when
+// the variance term is not positive, see ProbabilisticSharpeRatioFromMoments
+func MinTrackRecordLengthFromMoments(sr, benchmarkSR, skew, kurt, prob float64) float64 {
+	sigma := sharpeDispersion(sr, skew, kurt)
+	if math.IsNaN(sigma) {
+		return math.NaN()
+	}
+	if sr <= benchmarkSR {
+		return math.Inf(1)
+	}
+	z := distuv.UnitNormal.Quantile(prob)
+	return 1 + math.Pow(sigma*z/(sr-benchmarkSR), 2)
+}
+
+// - ExpectedMaxSharpe function
+// ExpectedMaxSharpe is the expected maximum Sharpe ratio of nTrials independent
+// strategies without skill whose Sharpe ratios have variance trialsVariance:
+// sqrt(V) * ((1 - gamma) * Phi^-1(1 - 1/N) + gamma * Phi^-1(1 - 1/(N e)))
+func ExpectedMaxSharpe(nTrials int, trialsVariance float64) float64 {
+	if nTrials < 2 {
+		return 0
+	}
+	n := float64(nTrials)
+	return math.Sqrt(trialsVariance) * ((1-eulerGamma)*distuv.UnitNormal.Quantile(1-1/n) +
+		eulerGamma*distuv.UnitNormal.Quantile(1-1/(n*math.E)))
+}
+
+// - DeflatedSharpeRatio function
+// DeflatedSharpeRatio is the ProbabilisticSharpeRatio against ExpectedMaxSharpe,
+// correcting the Sharpe ratio of the best of nTrials strategies for the selection
+// trialsVariance is the variance of the per period Sharpe ratios of the trials,
+// see TrialsVariance
+func DeflatedSharpeRatio(Ra []float64, Rf interface{}, nTrials int, trialsVariance float64) float64 {
+	return ProbabilisticSharpeRatio(Ra, Rf, ExpectedMaxSharpe(nTrials, trialsVariance))
+}
+
+// - TrialsVariance function
+// TrialsVariance is the sample variance of the Sharpe ratios of the trials
+func TrialsVariance(sharpes []float64) float64 {
+	if len(sharpes) < 2 {
+		return 0
+	}
+	return stat.Variance(sharpes, nil)
+}
+
+// * function for the per period Sharpe ratio, the "moment" skewness and kurtosis
+// of the excess returns and the number of observations
+func sharpeMoments(Ra []float64, Rf interface{}) (sr, skew, kurt float64, n int) {
+	rts := ReturnsCalculator{Ra}
+	excess := rts.Excess(Rf)
+	sr = stat.Mean(excess, nil) / stat.StdDev(excess, nil)
+	return sr, Skewness(excess, "moment"), Kurtosis(excess, "moment"), len(excess)
+}
+
+// * function for the standard deviation of the Sharpe ratio estimator times sqrt(n - 1)
+// NaN when the variance term is not positive
+func sharpeDispersion(sr, skew, kurt float64) float64 {
+	v := 1 - skew*sr + (kurt-1)/4*sr*sr
+	if !(v > 0) {
+		return math.NaN()
+	}
+	return math.Sqrt(v)
+}
@@ -0,0 +1,67 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test the Sharpe ratio inference on the example of Bailey and Lopez de Prado (2012),
+// also the example of ProbSharpeRatio and MinTrackRecord in PerformanceAnalytics:
+// a monthly Sharpe ratio of 2/sqrt(12) against 1/sqrt(12), skewness -0.72, kurtosis 5.78
+func TestSharpeRatioFromMoments(t *testing.T) {
+	sr, ref := 2/math.Sqrt(12), 1/math.Sqrt(12)
+	mtrl := MinTrackRecordLengthFromMoments(sr, ref, -0.72, 5.78, 0.95)
+	// about five years of monthly returns
+	assert.InDelta(t, 59.8950986865, mtrl, 1e-8)
+	// the minimum track record is where the probabilistic Sharpe ratio reaches 0.95
+	assert.Less(t, ProbabilisticSharpeRatioFromMoments(sr, ref, -0.72, 5.78, 59), 0.95)
+	assert.Greater(t, ProbabilisticSharpeRatioFromMoments(sr, ref, -0.72, 5.78, 60), 0.95)
+	assert.InDelta(t, 0.9486925195, ProbabilisticSharpeRatioFromMoments(sr, ref, -0.72, 5.78, 59), 1e-9)
+
+	// normal returns give the variance (1 + SR^2 / 2) / (n - 1) of Lo (2002)
+	z := (sr - ref) * math.Sqrt(59) / math.Sqrt(1+sr*sr/2)
+	assert.InDelta(t, 0.5*math.Erfc(-z/math.Sqrt2), ProbabilisticSharpeRatioFromMoments(sr, ref, 0, 3, 60), 1e-12)
+	// no evidence either way at the benchmark itself
+	assert.InDelta(t, 0.5, ProbabilisticSharpeRatioFromMoments(sr, sr, -0.72, 5.78, 60), 1e-12)
+
+	// the variance term vanishes for a two-valued sample with kurt = skew^2 + 1
+	assert.True(t, math.IsNaN(ProbabilisticSharpeRatioFromMoments(0.5, 0, 4, 17, 60)))
+	assert.True(t, math.IsNaN(MinTrackRecordLengthFromMoments(0.5, 0, 4, 17, 0.95)))
+	assert.True(t, math.IsNaN(ProbabilisticSharpeRatio([]float64{0.01, 0.01, 0.01}, 0.0, 0)))
+}
+
+// test the probabilistic and deflated Sharpe ratios of HAM1
+// the inputs are the Sharpe ratio 0.3201889 and the skewness -0.6588445 of
+// TestReturnSeries; the expected values follow from the formulas above
+func TestProbabilisticSharpeRatio(t *testing.T) {
+	ham1, err := ReadSeries("../data/managers.csv", "HAM1")
+	assert.NoError(t, err)
+	rf := 0.035 / 12
+
+	excess := ham1.Calculator().Excess(rf)
+	kurt := Kurtosis(excess, "moment")
+	assert.InDelta(t, 5.3615887598, kurt, 1e-9)
+	assert.Equal(t, ProbabilisticSharpeRatioFromMoments(ham1.SharpeRatio(rf, true), 0.1, Skewness(excess, "moment"), kurt, 132),
+		ham1.ProbabilisticSharpeRatio(rf, 0.1))
+
+	assert.InDelta(t, 0.9992797945, ham1.ProbabilisticSharpeRatio(rf, 0), 1e-9)
+	assert.InDelta(t, 0.9857834046, ham1.ProbabilisticSharpeRatio(rf, 0.1), 1e-9)
+	assert.InDelta(t, 0.5796172495, ProbabilisticSharpeRatio(ham1.Values, rf, 0.3), 1e-9)
+
+	assert.InDelta(t, 35.9073963800, ham1.MinTrackRecordLength(rf, 0, 0.95), 1e-7)
+	assert.InDelta(t, 74.8140655444, MinTrackRecordLength(ham1.Values, rf, 0.1, 0.95), 1e-7)
+	assert.True(t, math.IsInf(ham1.MinTrackRecordLength(rf, 0.5, 0.95), 1))
+
+	assert.InDelta(t, 0.2530602893, ExpectedMaxSharpe(100, 0.01), 1e-9)
+	assert.InDelta(t, 0.1627560757, ExpectedMaxSharpe(1000, 0.0025), 1e-9)
+	assert.Equal(t, 0.0, ExpectedMaxSharpe(1, 0.01))
+	assert.InDelta(t, 0.7479475548, ham1.DeflatedSharpeRatio(rf, 100, 0.01), 1e-9)
+	assert.InDelta(t, 0.9414106989, DeflatedSharpeRatio(ham1.Values, rf, 1000, 0.0025), 1e-9)
+	// a single trial is not deflated
+	assert.Equal(t, ham1.ProbabilisticSharpeRatio(rf, 0), ham1.DeflatedSharpeRatio(rf, 1, 0.01))
+
+	assert.InDelta(t, 0.05/3, TrialsVariance([]float64{0, 0.1, 0.2, 0.3}), 1e-12)
+	assert.Equal(t, 0.0, TrialsVariance([]float64{0.2}))
+}
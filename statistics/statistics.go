@@ -1,6 +1,7 @@
 package statistics
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/gonum/floats"
@@ -89,7 +90,8 @@ func MaxDrawdown(Ra []float64) float64 {
 }
 
 // - Drawdowns function
-// todo: seems useless at this moment
+// Drawdowns returns the drawdown of every period from the running peak,
+// 0 at a new high and negative below it, see FindDrawdowns for the episodes
 func Drawdowns(Ra []float64) []float64 {
 	cumulative := CumProdAdd(Ra, 1.0)
 	// add the initial value of 1.0
@@ -200,6 +202,80 @@ func DownsidePotential(data []float64, threshold float64, tag string) float64 {
 	return sum / length
 }
 
+// - LowerPartialMoment function
+// mean of (threshold - r)^n over the returns below the threshold, the
+// returns above it count as zero
+func LowerPartialMoment(data []float64, threshold float64, n float64) float64 {
+	sum := 0.0
+	for _, r := range data {
+		if r < threshold {
+			sum += math.Pow(threshold-r, n)
+		}
+	}
+	return sum / float64(len(data))
+}
+
+// - SortinoRatio function
+// mean excess return over the minimum acceptable return (MAR) divided by
+// the downside deviation below it; MAR is a float64 per period or a []float64
+// with one rate per observation
+func SortinoRatio(Ra []float64, MAR interface{}) float64 {
+	rts := ReturnsCalculator{Ra}
+	excess := rts.Excess(MAR)
+	return stat.Mean(excess, nil) / DownsideDeviation(excess, 0, "full")
+}
+
+// - UpsidePotentialRatio function
+// mean return above MAR divided by the downside deviation below it
+// tag is subset to average over the returns on each side, anything else
+// averages over all the returns
+func UpsidePotentialRatio(Ra []float64, MAR float64, tag string) float64 {
+	upside := 0.0
+	count := 0
+	for _, r := range Ra {
+		if r > MAR {
+			upside += r - MAR
+			count++
+		}
+	}
+	length := float64(len(Ra))
+	if tag == "subset" {
+		length = float64(count)
+	}
+	return (upside / length) / DownsideDeviation(Ra, MAR, tag)
+}
+
+// - OmegaRatio function
+// probability weighted gains above the threshold L over the losses below it
+func OmegaRatio(Ra []float64, L float64) float64 {
+	gains, losses := 0.0, 0.0
+	for _, r := range Ra {
+		if r > L {
+			gains += r - L
+		} else {
+			losses += L - r
+		}
+	}
+	return gains / losses
+}
+
+// - OmegaCurve function
+// the Omega ratio at each threshold
+func OmegaCurve(Ra []float64, thresholds []float64) []float64 {
+	curve := make([]float64, len(thresholds))
+	for i, L := range thresholds {
+		curve[i] = OmegaRatio(Ra, L)
+	}
+	return curve
+}
+
+// - Kappa function
+// mean excess return over MAR divided by the n-th root of the lower partial
+// moment of order n; Kappa(1) is Omega - 1 and Kappa(2) the Sortino ratio
+func Kappa(Ra []float64, MAR float64, n float64) float64 {
+	return (stat.Mean(Ra, nil) - MAR) / math.Pow(LowerPartialMoment(Ra, MAR, n), 1/n)
+}
+
 // - Hurst index function
 // A Hurst index between 0.5 and 1 suggests that the returns are persistent. At 0.5, the index suggests returns are totally
 // random. Between 0 and 0.5 it suggests that the returns are mean reverting.
@@ -215,22 +291,92 @@ func HurstIndex(data []float64) float64 {
     return result
 }
 
-// - MarketTiming function
-// TH for Treynor-Mazuy model
-// HM for Henriksson-Merton model
-func MarketTiming(Ra,Rb []float64,Rf interface{}, tag string) (Alpha, Beta ,Gamma float64) {
+// - define the market timing models
+type TimingModel int
+
+const (
+	// TreynorMazuy adds the squared benchmark excess return
+	TreynorMazuy TimingModel = iota
+	// HenrikssonMerton adds the benchmark excess return in down markets
+	HenrikssonMerton
+)
+
+// String returns the tag of the model
+func (m TimingModel) String() string {
+	switch m {
+	case TreynorMazuy:
+		return "TM"
+	case HenrikssonMerton:
+		return "HM"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseTimingModel maps a tag to a model, TM (or the older TH) and HM
+func ParseTimingModel(tag string) (TimingModel, error) {
+	switch tag {
+	case "TM", "TH":
+		return TreynorMazuy, nil
+	case "HM":
+		return HenrikssonMerton, nil
+	default:
+		return 0, fmt.Errorf("unknown market timing model %q", tag)
+	}
+}
+
+// - define the result of a market timing regression
+type MarketTimingResult struct {
+	Model TimingModel
+	Alpha float64
+	Beta  float64
+	Gamma float64
+	// OLS is the fitted regression of the excess returns on
+	// a constant, the benchmark excess returns and the timing term
+	OLS *OLS
+}
+
+// - Method for the standard error of gamma
+func (mt *MarketTimingResult) GammaStdErr() float64 {
+	return mt.OLS.StandardErrors().At(2, 0)
+}
+
+// - Method for the t statistic of gamma
+func (mt *MarketTimingResult) GammaTStat() float64 {
+	return mt.OLS.TStats().At(2, 0)
+}
+
+// - Method for the p value of gamma
+func (mt *MarketTimingResult) GammaPValue() float64 {
+	return mt.OLS.PValues().At(2, 0)
+}
+
+// - MarketTimingModel function
+// MarketTimingModel regresses the excess returns of Ra on the excess returns
+// of Rb and the timing term of the model; opts are passed to the OLS, e.g.
+// WithCovType(HAC) and WithMaxLags for Newey-West standard errors
+func MarketTimingModel(Ra, Rb []float64, Rf interface{}, model TimingModel, opts ...OptionOLS) (*MarketTimingResult, error) {
+	if len(Ra) != len(Rb) {
+		return nil, &LengthMismatchError{What: "MarketTiming", Expected: len(Ra), Got: len(Rb)}
+	}
 	rtsa := ReturnsCalculator{Ra}
 	rtsb := ReturnsCalculator{Rb}
 
 	// calculate the excess returns
-	ExcessRa := rtsa.Excess(Rf)
-	ExcessRb := rtsb.Excess(Rf)
+	ExcessRa, err := rtsa.TryExcess(Rf)
+	if err != nil {
+		return nil, err
+	}
+	ExcessRb, err := rtsb.TryExcess(Rf)
+	if err != nil {
+		return nil, err
+	}
 	// make a slice with the same length as the returns
 	D := make([]float64, len(ExcessRb))
-	switch tag {
-	case "TH":
-		copy(D,ExcessRb)
-	case "HM":
+	switch model {
+	case TreynorMazuy:
+		copy(D, ExcessRb)
+	case HenrikssonMerton:
 		for i := range ExcessRb {
 			if ExcessRb[i] < 0 {
 				D[i] = 1
@@ -239,10 +385,9 @@ func MarketTiming(Ra,Rb []float64,Rf interface{}, tag string) (Alpha, Beta ,Gamm
 			}
 		}
 	default:
-		copy(D,ExcessRb)
+		return nil, fmt.Errorf("unknown market timing model %d", model)
 	}
-	// let ExcessRa is y 
-	
+	// let ExcessRa is y
 	Y := mat.NewDense(len(ExcessRa), 1, ExcessRa)
 	// let ExcessRb and ExcessRb*D as Xs
 	Xs := mat.NewDense(len(ExcessRb), 3, nil)
@@ -252,14 +397,34 @@ func MarketTiming(Ra,Rb []float64,Rf interface{}, tag string) (Alpha, Beta ,Gamm
 		Xs.Set(i, 2, ExcessRb[i]*D[i])
 	}
 	// calculate the coefficients
-	olsres := NewOLS(Xs,Y)
-
-	olsres.Run()
+	olsres := NewOLS(Xs, Y, opts...)
+	if err := olsres.Run(); err != nil {
+		return nil, err
+	}
 
 	cos := olsres.Coefficients().RawMatrix().Data
-	Alpha = cos[0]
-	Beta = cos[1]
-	Gamma = cos[2]
+	return &MarketTimingResult{
+		Model: model,
+		Alpha: cos[0],
+		Beta:  cos[1],
+		Gamma: cos[2],
+		OLS:   olsres,
+	}, nil
+}
 
-	return 
-}
\ No newline at end of file
+// - MarketTiming function
+// TH for Treynor-Mazuy model
+// HM for Henriksson-Merton model
+// any other tag falls back to Treynor-Mazuy and errors give NaN,
+// use MarketTimingModel for the typed model, the errors and the inference
+func MarketTiming(Ra, Rb []float64, Rf interface{}, tag string) (Alpha, Beta, Gamma float64) {
+	model, err := ParseTimingModel(tag)
+	if err != nil {
+		model = TreynorMazuy
+	}
+	res, err := MarketTimingModel(Ra, Rb, Rf, model)
+	if err != nil {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	return res.Alpha, res.Beta, res.Gamma
+}
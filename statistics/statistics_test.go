@@ -233,4 +233,96 @@ func TestDownsideDeviation(t *testing.T) {
 	assert.InDelta(t, Alpha, 0.008275839, 0.0000001)
 	assert.InDelta(t, Beta, 0.3211407, 0.000001)
 	assert.InDelta(t, Gamma, 0.1344417, 0.000001)
-}
\ No newline at end of file
+}
+
+// TestSortinoOmegaKappa tests the ratios built on the downside functions
+func TestSortinoOmegaKappa(t *testing.T) {
+	bacon, err := ReadFrame("../data/portfolio_bacon.csv")
+	if err != nil {
+		panic(err)
+	}
+	rt, _ := bacon.Column("portfolio monthly return (%)")
+	MAR := 0.005
+
+	// these numbers are from the R code, rounded as in the package examples
+	assert.InDelta(t, 0.157, Kappa(rt, MAR, 2), 0.0005)
+	assert.InDelta(t, 0.29, Kappa(rt, MAR, 1), 0.005)
+
+	// more decimal places from the same formulas
+	assert.InDelta(t, 0.15663708, SortinoRatio(rt, MAR), 0.00000001)
+	assert.InDelta(t, 0.86670415, UpsidePotentialRatio(rt, MAR, "subset"), 0.00000001)
+	assert.InDelta(t, 0.69344539, UpsidePotentialRatio(rt, MAR, "full"), 0.00000001)
+	assert.InDelta(t, 1.77978339, OmegaRatio(rt, 0), 0.00000001)
+	assert.InDelta(t, 1.29179331, OmegaRatio(rt, MAR), 0.00000001)
+	assert.InDelta(t, 0.11964979, Kappa(rt, MAR, 3), 0.00000001)
+
+	// Kappa(2) is the Sortino ratio and Kappa(1) is Omega - 1
+	assert.InDelta(t, SortinoRatio(rt, MAR), Kappa(rt, MAR, 2), 0.0000000001)
+	assert.InDelta(t, OmegaRatio(rt, MAR)-1, Kappa(rt, MAR, 1), 0.0000000001)
+
+	// the Omega curve decreases with the threshold
+	curve := OmegaCurve(rt, []float64{-0.01, 0, 0.005, 0.01})
+	assert.InDelta(t, 1.77978339, curve[1], 0.00000001)
+	for i := 1; i < len(curve); i++ {
+		assert.Less(t, curve[i], curve[i-1])
+	}
+
+	// MAR as a series
+	rtp, _ := CheckPos(fds, "HAM1")
+	ham1, _ := TryStringToFloatSlice(GetSecondDimensionData(dt, rtp))
+	rfp, _ := CheckPos(fds, "US 3m TR")
+	rf, _ := TryStringToFloatSlice(GetSecondDimensionData(dt, rfp))
+	assert.InDelta(t, 0.76493340, SortinoRatio(ham1, 0.0), 0.00000001)
+	assert.InDelta(t, 0.50487028, SortinoRatio(ham1, rf), 0.00000001)
+}
+
+// TestMarketTimingModel tests the typed market timing regression
+func TestMarketTimingModel(t *testing.T) {
+	rtp, _ := CheckPos(fds, "HAM1")
+	rt, _ := TryStringToFloatSlice(GetSecondDimensionData(dt, rtp))
+	bmp, _ := CheckPos(fds, "SP500 TR")
+	bm, _ := TryStringToFloatSlice(GetSecondDimensionData(dt, bmp))
+	rf := .035 / 12
+
+	// same coefficients as the tag based function
+	res, err := MarketTimingModel(rt, bm, rf, TreynorMazuy)
+	assert.Nil(t, err)
+	assert.Equal(t, TreynorMazuy, res.Model)
+	assert.InDelta(t, 0.007856668, res.Alpha, 0.0000001)
+	assert.InDelta(t, 0.3786747, res.Beta, 0.000001)
+	assert.InDelta(t, -0.9646121, res.Gamma, 0.000001)
+	assert.Equal(t, 132, res.OLS.N())
+	assert.InDelta(t, res.Gamma/res.GammaStdErr(), res.GammaTStat(), 0.0000001)
+	assert.Greater(t, res.GammaPValue(), 0.05)
+
+	res, err = MarketTimingModel(rt, bm, rf, HenrikssonMerton)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.1344417, res.Gamma, 0.000001)
+
+	// Newey-West standard errors with 3 lags
+	// reference values of the statsmodels call
+	//   OLS(ham1 - rf, [1, x, x**2]).fit(cov_type='HAC', cov_kwds={'maxlags': 3})
+	// with x = sp500 - rf, from testdata/reference/timing.py which follows it
+	// as statsmodels is not available to the test suite
+	res, err = MarketTimingModel(rt, bm, rf, TreynorMazuy, WithCovType(HAC), WithMaxLags(3))
+	assert.Nil(t, err)
+	assert.Equal(t, HAC, res.OLS.CovType())
+	assert.InDelta(t, -0.9646121, res.Gamma, 0.000001)
+	assert.InDelta(t, 0.0019990380, res.OLS.StandardErrors().At(0, 0), 0.0000000001)
+	assert.InDelta(t, 0.0494110549, res.OLS.StandardErrors().At(1, 0), 0.0000000001)
+	assert.InDelta(t, 0.6897408592, res.GammaStdErr(), 0.0000000001)
+	assert.InDelta(t, 0.1619588302, res.GammaPValue(), 0.0000000001)
+
+	// the tags and the errors
+	model, err := ParseTimingModel("TH")
+	assert.Nil(t, err)
+	assert.Equal(t, TreynorMazuy, model)
+	_, err = ParseTimingModel("XX")
+	assert.NotNil(t, err)
+	_, err = MarketTimingModel(rt, bm[1:], rf, TreynorMazuy)
+	assert.NotNil(t, err)
+	_, err = MarketTimingModel(rt, bm, "0.01", TreynorMazuy)
+	assert.NotNil(t, err)
+	_, err = MarketTimingModel(rt, bm, rf, TimingModel(7))
+	assert.NotNil(t, err)
+}
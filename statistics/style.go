@@ -0,0 +1,310 @@
+package statistics
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// - define the constraint sets of the style weights
+type StyleConstraint int
+
+const (
+	// Unconstrained leaves the weights free, a regression without intercept on demeaned returns
+	Unconstrained StyleConstraint = iota
+	// SumToOne makes the weights add up to one
+	SumToOne
+	// LongOnly makes the weights add up to one and be non negative, Sharpe (1992)
+	LongOnly
+)
+
+// String returns the name of the constraint set
+func (sc StyleConstraint) String() string {
+	switch sc {
+	case SumToOne:
+		return "sum-to-one"
+	case LongOnly:
+		return "long-only"
+	default:
+		return "unconstrained"
+	}
+}
+
+// - define the outcome of a returns-based style analysis
+type StyleResult struct {
+	Constraint StyleConstraint
+	// Names label the style indexes, Weights are their exposures
+	Names   []string
+	Weights []float64
+	// RSquared is 1 - Var(fund - styles * weights) / Var(fund), the style share
+	RSquared float64
+	// Selection is 1 - RSquared, the share due to the manager's selection
+	Selection float64
+}
+
+// - StyleAnalysis function
+// StyleAnalysis finds the style weights w minimising the tracking variance
+// Var(fund - styles * w) under the constraint set, the centred objective of
+// Sharpe (1992): the mean of the residual is the selection return and does not
+// weigh on the fit; style.QPfit of PerformanceAnalytics minimises the uncentred
+// sum of squares instead, so its weights differ when the returns have a mean
+// styles[j] holds the returns of index j; the inequality constrained problem
+// is solved by a primal active-set method on the covariance matrices
+func StyleAnalysis(fund []float64, styles [][]float64, constraint StyleConstraint) (*StyleResult, error) {
+	k := len(styles)
+	if k == 0 {
+		return nil, errors.New("style: no style index")
+	}
+	for j := range styles {
+		if len(styles[j]) != len(fund) {
+			return nil, &LengthMismatchError{What: "style returns", Expected: len(fund), Got: len(styles[j])}
+		}
+	}
+	if len(fund) <= k {
+		return nil, errors.New("style: not enough observations")
+	}
+	// the objective is w'Q w - 2 c'w + Var(fund)
+	all := append(append([][]float64(nil), styles...), fund)
+	cov := CoVarianceMatrix(all)
+	q := mat.NewSymDense(k, nil)
+	c := make([]float64, k)
+	for i := 0; i < k; i++ {
+		for j := i; j < k; j++ {
+			q.SetSym(i, j, cov.At(i, j))
+		}
+		c[i] = cov.At(i, k)
+	}
+	varFund := cov.At(k, k)
+
+	var w []float64
+	var err error
+	switch constraint {
+	case SumToOne:
+		w, _, err = equalityQP(q, c, allIndexes(k))
+	case LongOnly:
+		w, err = longOnlyQP(q, c)
+	default:
+		var sol mat.VecDense
+		if err = sol.SolveVec(q, mat.NewVecDense(k, c)); err == nil {
+			w = mat.Col(nil, 0, &sol)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Var(fund - styles * w)
+	var qw mat.VecDense
+	wv := mat.NewVecDense(k, w)
+	qw.MulVec(q, wv)
+	residual := mat.Dot(wv, &qw) - 2*mat.Dot(wv, mat.NewVecDense(k, c)) + varFund
+	res := &StyleResult{
+		Constraint: constraint,
+		Names:      make([]string, k),
+		Weights:    w,
+		RSquared:   1 - residual/varFund,
+	}
+	res.Selection = 1 - res.RSquared
+	return res, nil
+}
+
+// * function for the indexes 0..k-1
+func allIndexes(k int) []int {
+	idx := make([]int, k)
+	for i := range idx {
+		idx[i] = i
+	}
+	return idx
+}
+
+// * function to minimise w'Q w - 2 c'w subject to sum(w) = 1 and w_j = 0 outside free
+// it solves the KKT system [Q_FF 1; 1' 0] [w_F; nu] = [c_F; 1] and returns the
+// full weights and nu, so that Q w - c + nu is the multiplier of each bound
+func equalityQP(q *mat.SymDense, c []float64, free []int) ([]float64, float64, error) {
+	k, _ := q.Dims()
+	m := len(free)
+	kkt := mat.NewDense(m+1, m+1, nil)
+	rhs := mat.NewVecDense(m+1, nil)
+	for a, i := range free {
+		for b, j := range free {
+			kkt.Set(a, b, q.At(i, j))
+		}
+		kkt.Set(a, m, 1)
+		kkt.Set(m, a, 1)
+		rhs.SetVec(a, c[i])
+	}
+	rhs.SetVec(m, 1)
+	var sol mat.VecDense
+	if err := sol.SolveVec(kkt, rhs); err != nil {
+		return nil, 0, err
+	}
+	w := make([]float64, k)
+	for a, i := range free {
+		w[i] = sol.AtVec(a)
+	}
+	return w, sol.AtVec(m), nil
+}
+
+// * function for the long-only weights by the primal active-set method
+// starting from equal weights, the working set holds the weights fixed at zero:
+// a step towards the equality solution on the free weights stops at the first
+// weight hitting zero, which joins the working set, and once the step is full
+// the weight with the most negative multiplier leaves it, until none is negative
+func longOnlyQP(q *mat.SymDense, c []float64) ([]float64, error) {
+	k := len(c)
+	const tol = 1e-12
+	w := make([]float64, k)
+	for i := range w {
+		w[i] = 1 / float64(k)
+	}
+	bound := make([]bool, k)
+	for iter := 0; iter < 100*k; iter++ {
+		free := make([]int, 0, k)
+		for i := 0; i < k; i++ {
+			if !bound[i] {
+				free = append(free, i)
+			}
+		}
+		p, nu, err := equalityQP(q, c, free)
+		if err != nil {
+			return nil, err
+		}
+		// the longest feasible step towards p
+		step, blocking := 1.0, -1
+		for _, i := range free {
+			if p[i] < w[i] && p[i] < 0 {
+				if s := w[i] / (w[i] - p[i]); s < step {
+					step, blocking = s, i
+				}
+			}
+		}
+		for _, i := range free {
+			w[i] += step * (p[i] - w[i])
+		}
+		if blocking >= 0 {
+			w[blocking] = 0
+			bound[blocking] = true
+			continue
+		}
+		// full step: check the multipliers of the bounds
+		worst, release := -tol, -1
+		for i := 0; i < k; i++ {
+			if !bound[i] {
+				continue
+			}
+			mu := -c[i] + nu
+			for j := 0; j < k; j++ {
+				mu += q.At(i, j) * w[j]
+			}
+			if mu < worst {
+				worst, release = mu, i
+			}
+		}
+		if release < 0 {
+			return w, nil
+		}
+		bound[release] = false
+	}
+	return nil, errors.New("style: the active-set method did not converge")
+}
+
+// - Method for the style analysis of the series on the columns of a frame
+// the series is aligned on the frame dates where every column is known
+func (rs *ReturnSeries) StyleAnalysis(styles *ReturnFrame, constraint StyleConstraint) (*StyleResult, error) {
+	_, fund, cols := alignOnFrame(rs, styles.Dates, styles.Columns)
+	res, err := StyleAnalysis(fund, cols, constraint)
+	if err != nil {
+		return nil, err
+	}
+	copy(res.Names, styles.Names)
+	return res, nil
+}
+
+// - define the style weights over moving windows
+type StyleDrift struct {
+	// Dates are the dates of the last observation of each window
+	Dates []time.Time
+	Names []string
+	// Weights hold one slice of style weights per window
+	Weights  [][]float64
+	RSquared []float64
+
+	periodicity Periodicity
+}
+
+// - Method for the rolling style analysis, the style drift of the series
+// the windows are laid out on the aligned dates with WithStep, WithExpanding
+// and WithMinObs; as RollingRegression, WithWorkers is an error
+func (rs *ReturnSeries) RollingStyle(styles *ReturnFrame, constraint StyleConstraint, window int, opts ...OptionRolling) (*StyleDrift, error) {
+	dates, fund, cols := alignOnFrame(rs, styles.Dates, styles.Columns)
+	spec := newRollingSpec(opts)
+	if spec.step < 1 {
+		return nil, errors.New("rolling: the step must be positive")
+	}
+	if spec.minimum(window) <= len(cols) || window > len(fund) {
+		return nil, errors.New("rolling: the window must exceed the number of styles and fit in the sample")
+	}
+	if spec.workers != 1 {
+		return nil, errors.New("rolling: RollingStyle runs on a single goroutine, drop WithWorkers")
+	}
+	drift := &StyleDrift{Names: styles.Names, periodicity: rs.Periodicity}
+	for _, w := range spec.windows(len(fund), window) {
+		sub := make([][]float64, len(cols))
+		for j := range cols {
+			sub[j] = cols[j][w[0] : w[1]+1]
+		}
+		res, err := StyleAnalysis(fund[w[0]:w[1]+1], sub, constraint)
+		if err != nil {
+			return nil, err
+		}
+		drift.Dates = append(drift.Dates, dates[w[1]])
+		drift.Weights = append(drift.Weights, res.Weights)
+		drift.RSquared = append(drift.RSquared, res.RSquared)
+	}
+	return drift, nil
+}
+
+// - Method for the series of the weight of style j
+func (sd *StyleDrift) Weight(j int) *ReturnSeries {
+	values := make([]float64, len(sd.Dates))
+	for i := range values {
+		values[i] = sd.Weights[i][j]
+	}
+	return &ReturnSeries{Name: sd.Names[j], Dates: sd.Dates, Values: values, Periodicity: sd.periodicity}
+}
+
+// * function to align a series on the rows of a frame where every column is known
+// it returns the common dates, the series values and the columns on those dates
+func alignOnFrame(rs *ReturnSeries, dates []time.Time, columns [][]float64) ([]time.Time, []float64, [][]float64) {
+	values := make(map[time.Time]float64, rs.Len())
+	for i, d := range rs.Dates {
+		values[d] = rs.Values[i]
+	}
+	outDates := make([]time.Time, 0, len(dates))
+	fund := make([]float64, 0, len(dates))
+	cols := make([][]float64, len(columns))
+	for i, d := range dates {
+		v, ok := values[d]
+		if !ok || math.IsNaN(v) {
+			continue
+		}
+		complete := true
+		for _, col := range columns {
+			if math.IsNaN(col[i]) {
+				complete = false
+				break
+			}
+		}
+		if !complete {
+			continue
+		}
+		outDates = append(outDates, d)
+		fund = append(fund, v)
+		for j, col := range columns {
+			cols[j] = append(cols[j], col[i])
+		}
+	}
+	return outDates, fund, cols
+}
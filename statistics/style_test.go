@@ -0,0 +1,97 @@
+package statistics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test the style analysis of HAM1 on the edhec and market indexes of managers.csv
+// expected values of the R calls on the sample covariances, Q = cov(styles)
+// and c = cov(styles, fund)
+//
+//	solve(Q, c)
+//	solve.QP(Q, c, matrix(1, 4), 1, meq = 1)$solution
+//	solve.QP(Q, c, cbind(1, diag(4)), c(1, rep(0, 4)), meq = 1)$solution
+//
+// from testdata/reference/style.py as R is not available to the test suite
+func TestStyleAnalysis(t *testing.T) {
+	fund, err := ReadSeries("../data/managers.csv", "HAM1")
+	assert.NoError(t, err)
+	managers, err := ReadFrame("../data/managers.csv")
+	assert.NoError(t, err)
+	styles, err := managers.Select("EDHEC LS EQ", "SP500 TR", "US 10Y TR", "US 3m TR")
+	assert.NoError(t, err)
+
+	cases := []struct {
+		constraint StyleConstraint
+		weights    []float64
+		rsquared   float64
+	}{
+		{Unconstrained, []float64{0.2692011181, 0.2868875606, -0.2293967780, 0.3385327445}, 0.5017673155},
+		{SumToOne, []float64{0.2677728713, 0.2871853986, -0.2302352396, 0.6752769698}, 0.5013962620},
+		{LongOnly, []float64{0.2776566215, 0.3062708989, 0, 0.4160724797}, 0.4713556935},
+	}
+	for _, c := range cases {
+		res, err := fund.StyleAnalysis(styles, c.constraint)
+		assert.NoError(t, err, c.constraint.String())
+		assert.Equal(t, styles.Names, res.Names)
+		for j, w := range c.weights {
+			assert.InDelta(t, w, res.Weights[j], 1e-8, c.constraint.String())
+		}
+		assert.InDelta(t, c.rsquared, res.RSquared, 1e-9, c.constraint.String())
+		assert.InDelta(t, 1-c.rsquared, res.Selection, 1e-9, c.constraint.String())
+	}
+
+	_, err = StyleAnalysis([]float64{1, 2, 3}, [][]float64{{1, 2}}, LongOnly)
+	var lme *LengthMismatchError
+	assert.ErrorAs(t, err, &lme)
+}
+
+// test the style drift against the analysis of each window
+func TestRollingStyle(t *testing.T) {
+	fund, err := ReadSeries("../data/managers.csv", "HAM1")
+	assert.NoError(t, err)
+	managers, err := ReadFrame("../data/managers.csv")
+	assert.NoError(t, err)
+	styles, err := managers.Select("EDHEC LS EQ", "SP500 TR", "US 10Y TR", "US 3m TR")
+	assert.NoError(t, err)
+
+	drift, err := fund.RollingStyle(styles, LongOnly, 36, WithStep(12))
+	assert.NoError(t, err)
+	// 120 aligned periods: windows ending at rows 35, 47, ..., 119
+	assert.Len(t, drift.Dates, 8)
+	assert.Equal(t, styles.Dates[len(styles.Dates)-1], drift.Dates[7])
+
+	_, y, cols := alignOnFrame(fund, styles.Dates, styles.Columns)
+	for i, end := range []int{35, 119} {
+		sub := make([][]float64, len(cols))
+		for j := range cols {
+			sub[j] = cols[j][end-35 : end+1]
+		}
+		res, err := StyleAnalysis(y[end-35:end+1], sub, LongOnly)
+		assert.NoError(t, err)
+		k := i * 7
+		for j := range cols {
+			assert.InDelta(t, res.Weights[j], drift.Weights[k][j], 1e-12)
+			assert.GreaterOrEqual(t, drift.Weights[k][j], 0.0)
+		}
+		assert.InDelta(t, res.RSquared, drift.RSquared[k], 1e-12)
+	}
+	for _, ws := range drift.Weights {
+		sum := 0.0
+		for _, w := range ws {
+			sum += w
+		}
+		assert.InDelta(t, 1, sum, 1e-10)
+	}
+
+	sp := drift.Weight(1)
+	assert.Equal(t, "SP500 TR", sp.Name)
+	assert.Equal(t, drift.Weights[3][1], sp.Values[3])
+
+	_, err = fund.RollingStyle(styles, LongOnly, 4)
+	assert.Error(t, err)
+	_, err = fund.RollingStyle(styles, LongOnly, 36, WithWorkers(2))
+	assert.Error(t, err)
+}
@@ -0,0 +1,263 @@
+package statistics
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// - define one row of the coefficient table of a regression summary
+type CoefficientRow struct {
+	Name   string  `json:"name"`
+	Coef   float64 `json:"coef"`
+	StdErr float64 `json:"std_err"`
+	Stat   float64 `json:"stat"`
+	PValue float64 `json:"p_value"`
+	Lower  float64 `json:"lower"`
+	Upper  float64 `json:"upper"`
+}
+
+// - define the content of a regression summary, as the statsmodels summary
+// Stat is a t statistic for the nonrobust covariance and a z statistic otherwise
+type RegressionSummary struct {
+	Model           string           `json:"model"`
+	DepVariable     string           `json:"dep_variable"`
+	CovType         string           `json:"cov_type"`
+	NObs            int              `json:"n_obs"`
+	DFResid         float64          `json:"df_resid"`
+	DFModel         float64          `json:"df_model"`
+	RSquared        float64          `json:"r_squared"`
+	AdjRSquared     float64          `json:"adj_r_squared"`
+	FStatistic      float64          `json:"f_statistic"`
+	FPValue         float64          `json:"f_p_value"`
+	LogLikelihood   float64          `json:"log_likelihood"`
+	AIC             float64          `json:"aic"`
+	BIC             float64          `json:"bic"`
+	Omnibus         float64          `json:"omnibus"`
+	OmnibusPValue   float64          `json:"omnibus_p_value"`
+	DurbinWatson    float64          `json:"durbin_watson"`
+	JarqueBera      float64          `json:"jarque_bera"`
+	JarqueBeraP     float64          `json:"jarque_bera_p_value"`
+	Skew            float64          `json:"skew"`
+	Kurtosis        float64          `json:"kurtosis"`
+	ConditionNumber float64          `json:"condition_number"`
+	Alpha           float64          `json:"alpha"`
+	Coefficients    []CoefficientRow `json:"coefficients"`
+}
+
+// summaryAlpha is the level of the confidence intervals of the summaries
+const summaryAlpha = 0.05
+
+// - Method to collect the summary of a fitted model
+func (ols *OLS) SummaryTable() (*RegressionSummary, error) {
+	if ols.coefficients == nil {
+		return nil, errors.New("ols: Run the model before the summary")
+	}
+	rs := &RegressionSummary{
+		Model:           ols.model,
+		DepVariable:     ols.YName(),
+		CovType:         ols.covType.String(),
+		NObs:            ols.N(),
+		DFResid:         ols.DF(),
+		DFModel:         ols.DFModel(),
+		RSquared:        ols.RSquared(),
+		AdjRSquared:     ols.AdjRSquared(),
+		FStatistic:      ols.FStatistic(),
+		FPValue:         ols.FPValue(),
+		LogLikelihood:   ols.LogLikelihood(),
+		AIC:             ols.AIC(),
+		BIC:             ols.BIC(),
+		DurbinWatson:    ols.DurbinWatson(),
+		Skew:            ols.ResidualSkew(),
+		Kurtosis:        ols.ResidualKurtosis(),
+		ConditionNumber: ols.ConditionNumber(),
+		Alpha:           summaryAlpha,
+	}
+	rs.Omnibus, rs.OmnibusPValue = ols.Omnibus()
+	rs.JarqueBera, rs.JarqueBeraP = ols.JarqueBera()
+	ci := ols.ConfInt(summaryAlpha)
+	for i, name := range ols.XNames() {
+		rs.Coefficients = append(rs.Coefficients, CoefficientRow{
+			Name:   name,
+			Coef:   ols.coefficients.At(i, 0),
+			StdErr: ols.standardErrors.At(i, 0),
+			Stat:   ols.tStats.At(i, 0),
+			PValue: ols.pValues.At(i, 0),
+			Lower:  ci.At(i, 0),
+			Upper:  ci.At(i, 1),
+		})
+	}
+	return rs, nil
+}
+
+// - Method for the statsmodels style text summary
+func (ols *OLS) Summary() (string, error) {
+	rs, err := ols.SummaryTable()
+	if err != nil {
+		return "", err
+	}
+	return rs.String(), nil
+}
+
+// - Method for the Markdown summary, for research notes
+func (ols *OLS) SummaryMarkdown() (string, error) {
+	rs, err := ols.SummaryTable()
+	if err != nil {
+		return "", err
+	}
+	return rs.Markdown(), nil
+}
+
+// - Method for the JSON summary
+// NaN and Inf, e.g. the Omnibus test below 8 observations, are encoded as null
+func (ols *OLS) SummaryJSON() ([]byte, error) {
+	rs, err := ols.SummaryTable()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(rs, "", "  ")
+}
+
+// - Method for the JSON encoding of the summary, non-finite numbers as null
+func (rs RegressionSummary) MarshalJSON() ([]byte, error) {
+	return marshalFinite(reflect.ValueOf(rs))
+}
+
+// - Method for the JSON encoding of a coefficient row, non-finite numbers as null
+func (row CoefficientRow) MarshalJSON() ([]byte, error) {
+	return marshalFinite(reflect.ValueOf(row))
+}
+
+// * function to encode the fields of a struct as encoding/json does,
+// except that the float64 fields holding NaN or Inf become null
+func marshalFinite(v reflect.Value) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i := 0; i < v.NumField(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		f := v.Field(i)
+		if f.Kind() == reflect.Float64 && (math.IsNaN(f.Float()) || math.IsInf(f.Float(), 0)) {
+			buf.WriteString("null")
+			continue
+		}
+		b, err := json.Marshal(f.Interface())
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// * the labels of the statistic and its p value columns
+func (rs *RegressionSummary) statLabels() (string, string) {
+	if rs.CovType == NonRobust.String() {
+		return "t", "P>|t|"
+	}
+	return "z", "P>|z|"
+}
+
+// * the header of the fit statistics as label value pairs, two per line
+func (rs *RegressionSummary) fitPairs() [][2]string {
+	return [][2]string{
+		{"Dep. Variable:", rs.DepVariable}, {"R-squared:", fmt.Sprintf("%.3f", rs.RSquared)},
+		{"Model:", rs.Model}, {"Adj. R-squared:", fmt.Sprintf("%.3f", rs.AdjRSquared)},
+		{"Method:", "Least Squares"}, {"F-statistic:", fmt.Sprintf("%.4g", rs.FStatistic)},
+		{"No. Observations:", fmt.Sprintf("%d", rs.NObs)}, {"Prob (F-statistic):", fmt.Sprintf("%.3g", rs.FPValue)},
+		{"Df Residuals:", fmt.Sprintf("%.0f", rs.DFResid)}, {"Log-Likelihood:", fmt.Sprintf("%.2f", rs.LogLikelihood)},
+		{"Df Model:", fmt.Sprintf("%.0f", rs.DFModel)}, {"AIC:", fmt.Sprintf("%.4g", rs.AIC)},
+		{"Covariance Type:", rs.CovType}, {"BIC:", fmt.Sprintf("%.4g", rs.BIC)},
+	}
+}
+
+// * the diagnostics of the residuals as label value pairs, two per line
+func (rs *RegressionSummary) diagnosticPairs() [][2]string {
+	return [][2]string{
+		{"Omnibus:", fmt.Sprintf("%.3f", rs.Omnibus)}, {"Durbin-Watson:", fmt.Sprintf("%.3f", rs.DurbinWatson)},
+		{"Prob(Omnibus):", fmt.Sprintf("%.3f", rs.OmnibusPValue)}, {"Jarque-Bera (JB):", fmt.Sprintf("%.3f", rs.JarqueBera)},
+		{"Skew:", fmt.Sprintf("%.3f", rs.Skew)}, {"Prob(JB):", fmt.Sprintf("%.3g", rs.JarqueBeraP)},
+		{"Kurtosis:", fmt.Sprintf("%.3f", rs.Kurtosis)}, {"Cond. No.:", fmt.Sprintf("%.3g", rs.ConditionNumber)},
+	}
+}
+
+// - Method for the text rendering of the summary
+func (rs *RegressionSummary) String() string {
+	nameWidth := 8
+	for _, row := range rs.Coefficients {
+		if len(row.Name) > nameWidth {
+			nameWidth = len(row.Name)
+		}
+	}
+	width := nameWidth + 6*11
+	if width < 78 {
+		width = 78
+	}
+	double := strings.Repeat("=", width) + "\n"
+	single := strings.Repeat("-", width) + "\n"
+	pairs := func(sb *strings.Builder, kv [][2]string) {
+		for i := 0; i+1 < len(kv); i += 2 {
+			fmt.Fprintf(sb, "%-20s%17s   %-20s%18s\n", kv[i][0], kv[i][1], kv[i+1][0], kv[i+1][1])
+		}
+	}
+
+	var sb strings.Builder
+	title := rs.Model + " Regression Results"
+	fmt.Fprintf(&sb, "%*s\n", (width+len(title))/2, title)
+	sb.WriteString(double)
+	pairs(&sb, rs.fitPairs())
+	sb.WriteString(double)
+	stat, pv := rs.statLabels()
+	lo, hi := rs.boundLabels()
+	fmt.Fprintf(&sb, "%-*s %10s %10s %10s %10s %10s %10s\n", nameWidth, "", "coef", "std err", stat, pv, lo, hi)
+	sb.WriteString(single)
+	for _, row := range rs.Coefficients {
+		fmt.Fprintf(&sb, "%-*s %10.4f %10.3f %10.3f %10.3f %10.3f %10.3f\n",
+			nameWidth, row.Name, row.Coef, row.StdErr, row.Stat, row.PValue, row.Lower, row.Upper)
+	}
+	sb.WriteString(double)
+	pairs(&sb, rs.diagnosticPairs())
+	sb.WriteString(double)
+	return sb.String()
+}
+
+// - Method for the Markdown rendering of the summary
+func (rs *RegressionSummary) Markdown() string {
+	escape := func(s string) string {
+		return strings.ReplaceAll(s, "|", "\\|")
+	}
+	var sb strings.Builder
+	sb.WriteString("| Statistic | Value | Statistic | Value |\n")
+	sb.WriteString("|---|---:|---|---:|\n")
+	for _, kv := range [][][2]string{rs.fitPairs(), rs.diagnosticPairs()} {
+		for i := 0; i+1 < len(kv); i += 2 {
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n",
+				escape(kv[i][0]), escape(kv[i][1]), escape(kv[i+1][0]), escape(kv[i+1][1]))
+		}
+	}
+	sb.WriteString("\n")
+	stat, pv := rs.statLabels()
+	lo, hi := rs.boundLabels()
+	fmt.Fprintf(&sb, "| | coef | std err | %s | %s | %s | %s |\n", stat, escape(pv), lo, hi)
+	sb.WriteString("|---|---:|---:|---:|---:|---:|---:|\n")
+	for _, row := range rs.Coefficients {
+		fmt.Fprintf(&sb, "| %s | %.4f | %.3f | %.3f | %.3f | %.3f | %.3f |\n",
+			escape(row.Name), row.Coef, row.StdErr, row.Stat, row.PValue, row.Lower, row.Upper)
+	}
+	return sb.String()
+}
+
+// * the labels of the confidence interval columns
+func (rs *RegressionSummary) boundLabels() (string, string) {
+	return fmt.Sprintf("[%g", rs.Alpha/2), fmt.Sprintf("%g]", 1-rs.Alpha/2)
+}
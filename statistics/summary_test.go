@@ -0,0 +1,83 @@
+package statistics
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+// test the summary renderings of the data.csv regression
+// the confidence intervals are those of the statsmodels summary
+func TestOLSSummary(t *testing.T) {
+	ols, err := ReadOLS("data.csv", true, "index_price", []string{"interest_rate", "unemployment_rate"})
+	assert.NoError(t, err)
+	assert.NoError(t, ols.Run())
+	assert.Equal(t, "index_price", ols.YName())
+	assert.Equal(t, []string{"const", "interest_rate", "unemployment_rate"}, ols.XNames())
+
+	ci := ols.ConfInt(0.05)
+	assert.InDelta(t, -71.685, ci.At(0, 0), 0.001)
+	assert.InDelta(t, 3668.493, ci.At(0, 1), 0.001)
+	assert.InDelta(t, 113.940, ci.At(1, 0), 0.001)
+	assert.InDelta(t, 577.140, ci.At(1, 1), 0.001)
+	assert.InDelta(t, -495.437, ci.At(2, 0), 0.001)
+	assert.InDelta(t, -4.856, ci.At(2, 1), 0.001)
+
+	text, err := ols.Summary()
+	assert.NoError(t, err)
+	for _, s := range []string{"OLS Regression Results", "index_price", "R-squared:", "0.898",
+		"F-statistic:", "92.07", "4.04e-11", "-134.61", "275.2", "278.8", "nonrobust",
+		"P>|t|", "[0.025", "0.975]", "interest_rate", "345.5401", "-495.437",
+		"Omnibus:", "2.691", "0.530", "1.551", "0.461", "-0.612", "3.226", "394"} {
+		assert.Contains(t, text, s)
+	}
+
+	md, err := ols.SummaryMarkdown()
+	assert.NoError(t, err)
+	assert.Contains(t, md, "| const | 1798.4040 | 899.248 | 2.000 | 0.059 | -71.685 | 3668.493 |")
+	assert.Contains(t, md, "P>\\|t\\|")
+
+	data, err := ols.SummaryJSON()
+	assert.NoError(t, err)
+	var rs RegressionSummary
+	assert.NoError(t, json.Unmarshal(data, &rs))
+	assert.Equal(t, 24, rs.NObs)
+	assert.Equal(t, "unemployment_rate", rs.Coefficients[2].Name)
+	assert.InDelta(t, 92.0727085692, rs.FStatistic, 1e-8)
+
+	// a robust covariance reports z statistics
+	robust := NewOLS(ols.X, ols.Y, WithCovType(HC1))
+	assert.NoError(t, robust.Run())
+	text, err = robust.Summary()
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(text, "P>|z|"))
+	assert.Equal(t, "x1", robust.XNames()[0])
+
+	// below 8 observations the Omnibus test is NaN, encoded as null
+	small := NewOLS(mat.DenseCopyOf(ols.X.Slice(0, 6, 0, 3)), mat.DenseCopyOf(ols.Y.Slice(0, 6, 0, 1)))
+	assert.NoError(t, small.Run())
+	data, err = small.SummaryJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"omnibus": null`)
+	var fields map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &fields))
+	assert.Nil(t, fields["omnibus_p_value"])
+	assert.Equal(t, 6.0, fields["n_obs"])
+
+	// an unfitted model has no summary
+	unfitted := NewOLS(ols.X, ols.Y)
+	_, err = unfitted.SummaryTable()
+	assert.Error(t, err)
+	_, err = unfitted.Summary()
+	assert.Error(t, err)
+	_, err = unfitted.SummaryJSON()
+	assert.Error(t, err)
+
+	// the names must match the columns of X
+	bad := NewOLS(ols.X, ols.Y, WithNames("y", "a"))
+	var lme *LengthMismatchError
+	assert.ErrorAs(t, bad.Run(), &lme)
+}
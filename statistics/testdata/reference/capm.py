@@ -0,0 +1,45 @@
+# reference values of TestCAPMTable and TestCAPMUpDown, the PerformanceAnalytics call
+#   table.CAPM(managers[, "HAM1"], managers[, "SP500 TR"], Rf = managers[, "US 3m TR"])
+# with CAPM.beta.bull and CAPM.beta.bear on the excess returns, and the
+# Rf = 0.035 / 12 and Rf = 0 variants of the same rows
+import math
+from common import *
+
+ra = vals("managers.csv", "HAM1")
+rb = vals("managers.csv", "SP500 TR")
+rfs = vals("managers.csv", "US 3m TR")
+
+
+def cov(x, y):
+    mx, my = mean(x), mean(y)
+    return sum((a - mx) * (b - my) for a, b in zip(x, y)) / (len(x) - 1)
+
+
+def geo(x):
+    return math.prod(1 + v for v in x) ** (12 / len(x)) - 1
+
+
+def run(rf):
+    xa = [a - f for a, f in zip(ra, rf)]
+    xb = [b - f for b, f in zip(rb, rf)]
+    beta = cov(xa, xb) / var(xb)
+    alpha = mean(xa) - beta * mean(xb)
+    corr = cov(xa, xb) / math.sqrt(var(xa) * var(xb))
+    eps = [a - alpha - beta * b for a, b in zip(xa, xb)]
+    spec = math.sqrt(cm(eps, 2) * 12)
+    sys = beta * math.sqrt(var(xb) * 12)
+    rfa = geo(rf)
+    jensen = geo(ra) - rfa - beta * (geo(rb) - rfa)
+    up = [(a, b) for a, b in zip(xa, xb) if b > 0]
+    down = [(a, b) for a, b in zip(xa, xb) if b <= 0]
+    bull = cov(*zip(*up)) / var([b for _, b in up])
+    bear = cov(*zip(*down)) / var([b for _, b in down])
+    print("alpha %.10f beta %.10f corr %.10f r2 %.10f annalpha %.10f" % (alpha, beta, corr, corr * corr, (1 + alpha) ** 12 - 1))
+    print("sys %.10f spec %.10f total %.10f treynor %.10f mtreynor %.10f" % (sys, spec, math.hypot(sys, spec), geo(xa) / beta, geo(xa) / sys))
+    print("jensen %.10f appraisal %.10f epsilon %.10f" % (jensen, jensen / spec, jensen - alpha * 12))
+    print("bull %.10f bear %.10f timing %.10f" % (bull, bear, bull / bear))
+
+
+run(rfs)
+run([0.035 / 12] * len(ra))
+run([0.0] * len(ra))
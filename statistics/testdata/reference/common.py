@@ -0,0 +1,48 @@
+# shared helpers of the reference scripts: csv loading and the
+# PerformanceAnalytics moment conventions, in plain Python 3
+import csv, math, os
+from statistics import NormalDist
+
+N = NormalDist()
+DATA = os.path.join(os.path.dirname(os.path.abspath(__file__)), "..", "..", "..", "data")
+
+
+def col(name, column):
+    with open(os.path.join(DATA, name)) as f:
+        r = csv.reader(f)
+        h = [x.strip() for x in next(r)]
+        i = h.index(column)
+        return [(row[0], float(row[i])) for row in r if row[i].strip() not in ("", "NA")]
+
+
+def vals(name, column):
+    return [v for _, v in col(name, column)]
+
+
+def mean(x):
+    return sum(x) / len(x)
+
+
+def var(x):
+    m = mean(x)
+    return sum((v - m) ** 2 for v in x) / (len(x) - 1)
+
+
+def cm(x, k):
+    m = mean(x)
+    return sum((v - m) ** k for v in x) / len(x)
+
+
+def skew(x):
+    return cm(x, 3) / cm(x, 2) ** 1.5
+
+
+def exkurt(x):
+    return cm(x, 4) / cm(x, 2) ** 2 - 3
+
+
+def q7(x, q):
+    s = sorted(x)
+    h = (len(s) - 1) * q
+    lo, hi = math.floor(h), math.ceil(h)
+    return s[lo] + (h - lo) * (s[hi] - s[lo])
@@ -0,0 +1,50 @@
+# reference values of TestOLSDiagnostics, the statsmodels calls
+#   res = OLS(index_price, add_constant(data[["interest_rate", "unemployment_rate"]])).fit()
+#   res.fvalue, res.llf, res.aic, res.bic, durbin_watson(res.resid),
+#   jarque_bera(res.resid), omni_normtest(res.resid)
+# omni_normtest is D'Agostino and Pearson's test of scipy.stats.normaltest
+import csv, math, os
+from linalg import *
+
+path = os.path.join(os.path.dirname(os.path.abspath(__file__)), "..", "..", "data.csv")
+rows = list(csv.DictReader(open(path)))
+X = [[1, float(r["interest_rate"]), float(r["unemployment_rate"])] for r in rows]
+y = [float(r["index_price"]) for r in rows]
+b, e, XtXi = ols(X, y)
+n, k = len(y), 3
+
+ssr = sum(v * v for v in e)
+ym = sum(y) / n
+tss = sum((v - ym) ** 2 for v in y)
+print("fvalue %.10f" % (((tss - ssr) / (k - 1)) / (ssr / (n - k))))
+llf = -n / 2 * math.log(2 * math.pi) - n / 2 * math.log(ssr / n) - n / 2
+print("llf %.10f aic %.10f bic %.10f" % (llf, -2 * llf + 2 * k, -2 * llf + math.log(n) * k))
+print("durbin_watson %.10f" % (sum((e[i] - e[i - 1]) ** 2 for i in range(1, n)) / ssr))
+
+m = sum(e) / n
+m2, m3, m4 = (sum((v - m) ** p for v in e) / n for p in (2, 3, 4))
+S, K = m3 / m2 ** 1.5, m4 / m2 ** 2
+print("skew %.10f kurtosis %.10f" % (S, K))
+jb = n / 6 * (S * S + (K - 3) ** 2 / 4)
+# the chi-squared survival function with 2 degrees of freedom is exp(-x / 2)
+print("jarque_bera %.10f pvalue %.10f" % (jb, math.exp(-jb / 2)))
+
+# skewtest
+y_ = S * math.sqrt((n + 1) * (n + 3) / (6.0 * (n - 2)))
+beta2 = 3.0 * (n * n + 27 * n - 70) * (n + 1) * (n + 3) / ((n - 2.0) * (n + 5) * (n + 7) * (n + 9))
+W2 = -1 + math.sqrt(2 * (beta2 - 1))
+delta = 1 / math.sqrt(0.5 * math.log(W2))
+alpha = math.sqrt(2.0 / (W2 - 1))
+Zs = delta * math.log(y_ / alpha + math.sqrt((y_ / alpha) ** 2 + 1))
+# kurtosistest
+E = 3.0 * (n - 1) / (n + 1)
+varb2 = 24.0 * n * (n - 2) * (n - 3) / ((n + 1) * (n + 1.0) * (n + 3) * (n + 5))
+x = (K - E) / math.sqrt(varb2)
+sb1 = 6.0 * (n * n - 5 * n + 2) / ((n + 7) * (n + 9)) * math.sqrt(6.0 * (n + 3) * (n + 5) / (n * (n - 2) * (n - 3)))
+A = 6.0 + 8.0 / sb1 * (2.0 / sb1 + math.sqrt(1 + 4.0 / sb1 ** 2))
+t1 = 1 - 2 / (9.0 * A)
+den = 1 + x * math.sqrt(2 / (A - 4.0))
+t2 = math.copysign(1, den) * ((1 - 2.0 / A) / abs(den)) ** (1 / 3.0)
+Zk = (t1 - t2) / math.sqrt(2 / (9.0 * A))
+om = Zs * Zs + Zk * Zk
+print("omnibus %.10f pvalue %.10f" % (om, math.exp(-om / 2)))
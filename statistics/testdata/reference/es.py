@@ -0,0 +1,47 @@
+# reference values of TestES, the PerformanceAnalytics calls
+#   ES(edhec[, j], p = p, method = "historical" | "gaussian" | "modified")
+# gaussian scales by the 1/n standard deviation as ES.Gaussian does,
+# modified follows mES with its Ipower integrals
+from common import *
+
+
+def Ipower(power,h):
+    fullprod=1
+    if power%2==0:
+        pstar=power//2
+        for j in range(1,pstar+1): fullprod*=2*j
+        I=fullprod*N.pdf(h)
+        for i in range(1,pstar+1):
+            prod=1
+            for j in range(1,i+1): prod*=2*j
+            I+=(fullprod/prod)*(h**(2*i))*N.pdf(h)
+    else:
+        pstar=(power-1)//2
+        for j in range(0,pstar+1): fullprod*=2*j+1
+        I=-fullprod*N.cdf(h)
+        for i in range(0,pstar+1):
+            prod=1
+            for j in range(0,i+1): prod*=2*j+1
+            I+=(fullprod/prod)*(h**(2*i+1))*N.pdf(h)
+    return I
+def mES(x,p):
+    a=1-p; z=N.inv_cdf(a); s=skew(x); k=exkurt(x)
+    h=z+(z*z-1)*s/6+(z**3-3*z)*k/24-(2*z**3-5*z)*s*s/36
+    I1,I2,I3,I4,I6=[Ipower(q,h) for q in (1,2,3,4,6)]
+    E=N.pdf(h)
+    E+=(1/24)*(I4-6*I2+3*N.pdf(h))*k
+    E+=(1/6)*(I3-3*I1)*s
+    E+=(1/72)*(I6-15*I4+45*I2-15*N.pdf(h))*s*s
+    E/=a
+    return mean(x)+cm(x,2)**.5*min(-E,h)
+
+
+for nm in ["Convertible Arbitrage", "CTA Global", "Emerging Markets"]:
+    x = vals("edhec.csv", nm)
+    for p in (0.95, 0.99):
+        a = 1 - p
+        z = N.inv_cdf(a)
+        v = q7(x, a)
+        hist = mean([r for r in x if r <= v])
+        g = mean(x) - cm(x, 2) ** 0.5 * N.pdf(z) / a
+        print(nm, p, "historical %.10f gaussian %.10f modified %.10f" % (hist, g, mES(x, p)))
@@ -0,0 +1,23 @@
+# reference values of TestFactorModel, the statsmodels call
+#   OLS(ham1 - rf, add_constant(managers[["SP500 TR", "US 10Y TR"]])).fit(cov_type="HAC", cov_kwds={"maxlags": 4})
+# with rf = managers["US 3m TR"]; 4 is the automatic lag floor(4 * (132 / 100)^(2/9))
+import math
+from common import *
+from linalg import *
+
+names = ["HAM1", "SP500 TR", "US 10Y TR", "US 3m TR"]
+cols = {c: dict(col("managers.csv", c)) for c in names}
+dates = [d for d, _ in col("managers.csv", "HAM1") if all(d in cols[c] for c in names)]
+X = [[1, cols["SP500 TR"][d], cols["US 10Y TR"][d]] for d in dates]
+y = [cols["HAM1"][d] - cols["US 3m TR"][d] for d in dates]
+n = len(y)
+L = int(math.floor(4 * (n / 100) ** (2 / 9)))
+b, e, XtXi = ols(X, y)
+C = hac(X, e, XtXi, L)
+se = [C[i][i] ** 0.5 for i in range(3)]
+t = [b[i] / se[i] for i in range(3)]
+ym = sum(y) / n
+print("nobs", n, "maxlags", L)
+print("params", ["%.10f" % v for v in b], "alpha * 12 %.10f" % (b[0] * 12))
+print("tvalues", ["%.8f" % v for v in t], "pvalues", ["%.10f" % (2 * (1 - N.cdf(abs(v)))) for v in t])
+print("rsquared %.10f" % (1 - sum(v * v for v in e) / sum((v - ym) ** 2 for v in y)))
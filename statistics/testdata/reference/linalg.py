@@ -0,0 +1,51 @@
+# small dense linear algebra for the regression reference scripts
+# the least squares solution, the classical inverse of X'X and the
+# Newey-West covariance with Bartlett weights, no small sample correction
+
+
+def T(A):
+    return [list(r) for r in zip(*A)]
+
+
+def mm(A, B):
+    return [[sum(a * b for a, b in zip(r, c)) for c in zip(*B)] for r in A]
+
+
+def inv(A):
+    n = len(A)
+    M = [list(r) + [1.0 if i == j else 0.0 for j in range(n)] for i, r in enumerate(A)]
+    for c in range(n):
+        p = max(range(c, n), key=lambda r: abs(M[r][c]))
+        M[c], M[p] = M[p], M[c]
+        pv = M[c][c]
+        M[c] = [v / pv for v in M[c]]
+        for r in range(n):
+            if r != c:
+                f = M[r][c]
+                M[r] = [a - f * b for a, b in zip(M[r], M[c])]
+    return [r[n:] for r in M]
+
+
+def ols(X, y):
+    Xt = T(X)
+    XtXi = inv(mm(Xt, X))
+    b = [r[0] for r in mm(XtXi, mm(Xt, [[v] for v in y]))]
+    e = [yi - sum(bi * xi for bi, xi in zip(b, row)) for yi, row in zip(y, X)]
+    return b, e, XtXi
+
+
+def hac(X, e, XtXi, L):
+    n, k = len(X), len(X[0])
+    S = [[0.0] * k for _ in range(k)]
+    sc = [[x * ei for x in row] for row, ei in zip(X, e)]
+    for t in range(n):
+        for i in range(k):
+            for j in range(k):
+                S[i][j] += sc[t][i] * sc[t][j]
+    for l in range(1, L + 1):
+        w = 1 - l / (L + 1)
+        for t in range(l, n):
+            for i in range(k):
+                for j in range(k):
+                    S[i][j] += w * (sc[t][i] * sc[t - l][j] + sc[t - l][i] * sc[t][j])
+    return mm(mm(XtXi, S), XtXi)
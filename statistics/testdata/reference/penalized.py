@@ -0,0 +1,55 @@
+# reference values of TestPenalizedRegression, the calls
+#   glmnet(X, y, alpha = 1, lambda = 0.002, thresh = 1e-14)
+#   Ridge(alpha=n * 0.001).fit(Z, y)
+#   ElasticNet(alpha=0.002, l1_ratio=0.5, tol=1e-14).fit(Z, y)
+# for HAM1 on HAM3, HAM4, SP500 TR, US 10Y TR and US 3m TR, the rows without NA;
+# Z is X scaled by the population standard deviation (StandardScaler), the
+# coefficients are reported back on the scale of X
+import math
+from common import *
+from linalg import *
+
+names = ["HAM3", "HAM4", "SP500 TR", "US 10Y TR", "US 3m TR"]
+cols = {c: dict(col("managers.csv", c)) for c in ["HAM1"] + names}
+dates = [d for d, _ in col("managers.csv", "HAM1") if all(d in cols[c] for c in names)]
+X = [[cols[c][d] for c in names] for d in dates]
+y = [cols["HAM1"][d] for d in dates]
+n, k = len(y), len(names)
+mu = [sum(x[j] for x in X) / n for j in range(k)]
+sd = [math.sqrt(sum((x[j] - mu[j]) ** 2 for x in X) / n) for j in range(k)]
+Z = [[(x[j] - mu[j]) / sd[j] for j in range(k)] for x in X]
+ym = mean(y)
+yc = [v - ym for v in y]
+
+
+def back(b):
+    c = [b[j] / sd[j] for j in range(k)]
+    return ym - sum(c[j] * mu[j] for j in range(k)), c
+
+
+def descent(lam, a):
+    b = [0.0] * k
+    for _ in range(100000):
+        mx = 0
+        for j in range(k):
+            res = [yc[i] - sum(Z[i][l] * b[l] for l in range(k)) for i in range(n)]
+            rho = sum(Z[i][j] * res[i] for i in range(n)) / n + b[j]
+            g = lam * a
+            nb = (rho - g if rho > g else rho + g if rho < -g else 0) / (1 + lam * (1 - a))
+            mx = max(mx, abs(nb - b[j]))
+            b[j] = nb
+        if mx < 1e-13:
+            break
+    return b
+
+
+print("n", n)
+lam = 0.001
+A = [[sum(z[i] * z[j] for z in Z) / n + (lam if i == j else 0) for j in range(k)] for i in range(k)]
+r = [sum(z[i] * v for z, v in zip(Z, yc)) / n for i in range(k)]
+b0, c = back([row[0] for row in mm(inv(A), [[v] for v in r])])
+print("ridge", lam, "%.12f" % b0, ["%.12f" % v for v in c])
+print("lambda max %.12f" % max(abs(sum(z[j] * v for z, v in zip(Z, yc)) / n) for j in range(k)))
+for lam, a in ((0.002, 1), (0.002, 0.5)):
+    b0, c = back(descent(lam, a))
+    print("elastic net", lam, a, "%.12f" % b0, ["%.12f" % v for v in c])
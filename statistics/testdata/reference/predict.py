@@ -0,0 +1,22 @@
+# reference values of TestOLSPredict, the statsmodels call
+#   OLS(index_price, add_constant(data[["interest_rate", "unemployment_rate"]])).fit()
+#       .get_prediction(exog).summary_frame(alpha=0.05)
+# at the rows [1, 2.5, 5.5] and [1, 1.75, 6.1]; the columns are mean, mean_se,
+# mean_ci_lower, mean_ci_upper, obs_ci_lower and obs_ci_upper
+import csv, math, os
+from linalg import *
+
+path = os.path.join(os.path.dirname(os.path.abspath(__file__)), "..", "..", "data.csv")
+rows = list(csv.DictReader(open(path)))
+X = [[1, float(r["interest_rate"]), float(r["unemployment_rate"])] for r in rows]
+y = [float(r["index_price"]) for r in rows]
+b, e, XtXi = ols(X, y)
+n, k = len(y), 3
+s2 = sum(v * v for v in e) / (n - k)
+# scipy.stats.t.ppf(0.975, 21)
+t = 2.079613844727662
+for x in ([1, 2.5, 5.5], [1, 1.75, 6.1]):
+    m = sum(a * c for a, c in zip(x, b))
+    v = sum(x[i] * XtXi[i][j] * x[j] for i in range(k) for j in range(k)) * s2
+    se, so = math.sqrt(v), math.sqrt(v + s2)
+    print("%.8f %.8f %.8f %.8f %.8f %.8f" % (m, se, m - t * se, m + t * se, m - t * so, m + t * so))
@@ -0,0 +1,34 @@
+# reference values of TestRobustCovariance, the statsmodels calls
+#   OLS(index_price, add_constant(data[["interest_rate", "unemployment_rate"]])).fit(cov_type=c)
+#   for c in "HC0", "HC1", "HC2", "HC3", and cov_type="HAC" with the default
+#   maxlags of floor(4 * (n / 100)^(2/9)); the data is statistics/data.csv
+import csv, math, os
+from common import N
+from linalg import *
+
+path = os.path.join(os.path.dirname(os.path.abspath(__file__)), "..", "..", "data.csv")
+rows = list(csv.DictReader(open(path)))
+X = [[1, float(r["interest_rate"]), float(r["unemployment_rate"])] for r in rows]
+y = [float(r["index_price"]) for r in rows]
+b, e, XtXi = ols(X, y)
+n, k = len(y), 3
+
+
+def white(kind):
+    S = [[0.0] * k for _ in range(k)]
+    for t in range(n):
+        h = sum(X[t][i] * XtXi[i][j] * X[t][j] for i in range(k) for j in range(k))
+        w = {"HC0": 1, "HC1": n / (n - k), "HC2": 1 / (1 - h), "HC3": 1 / (1 - h) ** 2}[kind]
+        for i in range(k):
+            for j in range(k):
+                S[i][j] += w * e[t] ** 2 * X[t][i] * X[t][j]
+    return mm(mm(XtXi, S), XtXi)
+
+
+for kind in ("HC0", "HC1", "HC2", "HC3"):
+    C = white(kind)
+    print(kind, "bse", ["%.10f" % (C[i][i] ** 0.5) for i in range(k)])
+L = int(math.floor(4 * (n / 100) ** (2 / 9)))
+C = hac(X, e, XtXi, L)
+print("HAC maxlags", L, "bse", ["%.10f" % (C[i][i] ** 0.5) for i in range(k)],
+      "pvalues", ["%.10f" % (2 * (1 - N.cdf(abs(b[i] / C[i][i] ** 0.5)))) for i in range(k)])
@@ -0,0 +1,54 @@
+# reference values of TestStyleAnalysis, the R calls (quadprog)
+#   solve(Q, c)
+#   solve.QP(Q, c, matrix(1, 4), 1, meq = 1)$solution
+#   solve.QP(Q, c, cbind(1, diag(4)), c(1, rep(0, 4)), meq = 1)$solution
+# with Q = cov(styles) and c = cov(styles, fund) for HAM1 on EDHEC LS EQ,
+# SP500 TR, US 10Y TR and US 3m TR; the long-only solution is found by
+# enumerating the active sets, R^2 is 1 - Var(fund - styles w) / Var(fund)
+import itertools
+from common import *
+from linalg import *
+
+S = ["EDHEC LS EQ", "SP500 TR", "US 10Y TR", "US 3m TR"]
+cols = {c: dict(col("managers.csv", c)) for c in ["HAM1"] + S}
+dates = [d for d, _ in col("managers.csv", "HAM1") if all(d in cols[c] for c in S)]
+y = [cols["HAM1"][d] for d in dates]
+X = [[cols[c][d] for d in dates] for c in S]
+k = len(S)
+
+
+def cov(a, b):
+    ma, mb = mean(a), mean(b)
+    return sum((p - ma) * (q - mb) for p, q in zip(a, b)) / (len(a) - 1)
+
+
+Q = [[cov(X[i], X[j]) for j in range(k)] for i in range(k)]
+c = [cov(X[i], y) for i in range(k)]
+vy = cov(y, y)
+
+
+def objective(w):
+    return sum(w[i] * Q[i][j] * w[j] for i in range(k) for j in range(k)) - 2 * sum(w[i] * c[i] for i in range(k)) + vy
+
+
+def sum_to_one(free):
+    m = len(free)
+    A = [[Q[free[i]][free[j]] for j in range(m)] + [1] for i in range(m)] + [[1] * m + [0]]
+    rhs = [[c[free[i]]] for i in range(m)] + [[1]]
+    sol = [r[0] for r in mm(inv(A), rhs)]
+    w = [0.0] * k
+    for i, f in enumerate(free):
+        w[f] = sol[i]
+    return w
+
+
+print("nobs", len(y))
+for name, w in (("unconstrained", [r[0] for r in mm(inv(Q), [[v] for v in c])]), ("sum-to-one", sum_to_one(list(range(k))))):
+    print(name, ["%.10f" % v for v in w], "r2 %.10f" % (1 - objective(w) / vy))
+best = None
+for m in range(1, k + 1):
+    for free in itertools.combinations(range(k), m):
+        w = sum_to_one(list(free))
+        if min(w) >= -1e-14 and (best is None or objective(w) < best[0]):
+            best = (objective(w), w)
+print("long-only", ["%.10f" % v for v in best[1]], "r2 %.10f" % (1 - best[0] / vy))
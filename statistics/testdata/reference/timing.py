@@ -0,0 +1,16 @@
+# reference values of the Newey-West case of TestMarketTimingModel, the statsmodels call
+#   sm.OLS(ham1 - rf, np.column_stack([np.ones(n), x, x ** 2])).fit(cov_type="HAC", cov_kwds={"maxlags": 3})
+# with x = sp500 - rf and rf = 0.035 / 12, the Treynor-Mazuy regression
+from common import *
+from linalg import *
+
+rf = 0.035 / 12
+ya = [a - rf for a in vals("managers.csv", "HAM1")]
+xb = [b - rf for b in vals("managers.csv", "SP500 TR")]
+X = [[1, b, b * b] for b in xb]
+b, e, XtXi = ols(X, ya)
+C = hac(X, e, XtXi, 3)
+se = [C[i][i] ** 0.5 for i in range(3)]
+print("params", ["%.10f" % v for v in b])
+print("bse", ["%.10f" % v for v in se])
+print("pvalues", ["%.10f" % (2 * (1 - N.cdf(abs(bi / s)))) for bi, s in zip(b, se)])
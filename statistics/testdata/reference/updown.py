@@ -0,0 +1,21 @@
+# reference values of the up/down ratios of TestCAPMUpDown, the PerformanceAnalytics calls
+#   UpDownRatios(managers[, "HAM1"], managers[, "SP500 TR"], method = m, side = s)
+#   for m in "Capture", "Number", "Percent" and s in "Up", "Down"
+# the down market periods are those with a benchmark return <= 0
+from common import *
+
+ra = vals("managers.csv", "HAM1")
+rb = vals("managers.csv", "SP500 TR")
+up = [(a, b) for a, b in zip(ra, rb) if b > 0]
+down = [(a, b) for a, b in zip(ra, rb) if b <= 0]
+
+
+def share(periods, beats):
+    return sum(1 for a, b in periods if beats(a, b)) / len(periods)
+
+
+print("capture up %.8f down %.8f" % (mean([a for a, _ in up]) / mean([b for _, b in up]),
+                                     mean([a for a, _ in down]) / mean([b for _, b in down])))
+print("number up %.8f down %.8f" % (share(up, lambda a, b: a > 0), share(down, lambda a, b: a < 0)))
+print("percent up %.8f down %.8f" % (share(up, lambda a, b: a > b), share(down, lambda a, b: a > b)))
+print("batting average %.8f" % (sum(1 for a, b in zip(ra, rb) if a > b) / len(ra)))
@@ -0,0 +1,15 @@
+# reference values of TestVaR, the PerformanceAnalytics calls
+#   VaR(edhec[, j], p = p, method = "historical" | "gaussian" | "modified")
+# historical is the type 7 quantile, gaussian and modified scale by the
+# 1/n standard deviation as VaR.Gaussian and VaR.CornishFisher do
+from common import *
+
+for nm in ["Convertible Arbitrage", "CTA Global"]:
+    x = vals("edhec.csv", nm)
+    for p in (0.95, 0.99):
+        a = 1 - p
+        z = N.inv_cdf(a)
+        s, k = skew(x), exkurt(x)
+        h = z + (z * z - 1) * s / 6 + (z ** 3 - 3 * z) * k / 24 - (2 * z ** 3 - 5 * z) * s * s / 36
+        sd = cm(x, 2) ** 0.5
+        print(nm, p, "historical %.10f gaussian %.10f modified %.10f" % (q7(x, a), mean(x) + z * sd, mean(x) + h * sd))
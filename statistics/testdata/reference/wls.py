@@ -0,0 +1,55 @@
+# reference values of TestWLS and TestGLSAR, the statsmodels calls
+#   WLS(index_price, X, weights=0.5 ** ((n - 1 - t) / 12)).fit()
+#   GLSAR(index_price, X, rho=1).iterative_fit(maxiter=100, rtol=1e-8)
+# with X = add_constant(data[["interest_rate", "unemployment_rate"]]); rho is
+# re-estimated by yule_walker(resid, order=1) after each Cochrane-Orcutt fit
+import csv, math, os
+from linalg import *
+
+path = os.path.join(os.path.dirname(os.path.abspath(__file__)), "..", "..", "data.csv")
+rows = list(csv.DictReader(open(path)))
+X = [[1, float(r["interest_rate"]), float(r["unemployment_rate"])] for r in rows]
+y = [float(r["index_price"]) for r in rows]
+n, k = len(y), 3
+
+w = [0.5 ** ((n - 1 - t) / 12) for t in range(n)]
+sw = [math.sqrt(v) for v in w]
+wX = [[x * s for x in row] for row, s in zip(X, sw)]
+wy = [v * s for v, s in zip(y, sw)]
+b, we, XtXi = ols(wX, wy)
+ssr = sum(v * v for v in we)
+s2 = ssr / (n - k)
+ym = sum(a * c for a, c in zip(w, y)) / sum(w)
+tss = sum(a * (c - ym) ** 2 for a, c in zip(w, y))
+r2 = 1 - ssr / tss
+llf = -n / 2 * math.log(2 * math.pi) - n / 2 * math.log(ssr / n) - n / 2 + 0.5 * sum(math.log(v) for v in w)
+print("WLS weights[0] %.10f" % w[0])
+print("params", ["%.8f" % v for v in b], "bse", ["%.8f" % math.sqrt(s2 * XtXi[i][i]) for i in range(k)])
+print("rsquared %.10f rsquared_adj %.10f llf %.8f fvalue %.8f" % (
+    r2, 1 - (1 - r2) * (n - 1) / (n - k), llf, ((tss - ssr) / 2) / (ssr / (n - k))))
+
+
+def yule_walker(e):
+    m = sum(e) / len(e)
+    d = [v - m for v in e]
+    return (sum(d[t] * d[t - 1] for t in range(1, len(d))) / (len(d) - 1)) / (sum(v * v for v in d) / len(d))
+
+
+rho = 0.0
+for it in range(1, 101):
+    Xw = [[X[t][j] - rho * X[t - 1][j] for j in range(k)] for t in range(1, n)]
+    yw = [y[t] - rho * y[t - 1] for t in range(1, n)]
+    b, e, XtXi = ols(Xw, yw)
+    resid = [yi - sum(bi * xi for bi, xi in zip(b, row)) for yi, row in zip(y, X)]
+    new = yule_walker(resid)
+    if abs(new - rho) < 1e-8:
+        break
+    rho = new
+m = n - 1
+ssr = sum(v * v for v in e)
+s2 = ssr / (m - k)
+ym = sum(yw) / m
+tss = sum((v - ym) ** 2 for v in yw)
+print("GLSAR iterations", it, "rho %.10f" % rho)
+print("params", ["%.8f" % v for v in b], "bse", ["%.8f" % math.sqrt(s2 * XtXi[i][i]) for i in range(k)],
+      "rsquared %.10f" % (1 - ssr / tss))
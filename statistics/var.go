@@ -0,0 +1,69 @@
+package statistics
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// - VaR function
+// Value-at-Risk at confidence level p (e.g. 0.95 or 0.99)
+// the sign convention follows PerformanceAnalytics: the result is the
+// return quantile, so a loss is reported as a negative number
+// tag selects the method:
+// historical for the empirical quantile,
+// gaussian for mean + z * standard deviation, the 1/n one as in VaR.Gaussian,
+// modified for the Cornish-Fisher expansion using Skewness "moment" and Kurtosis "excess"
+// any other tag falls back to modified, the PerformanceAnalytics default
+func VaR(R []float64, p float64, tag string) float64 {
+	if len(R) == 0 || p <= 0 || p >= 1 {
+		return math.NaN()
+	}
+	alpha := 1 - p
+	switch tag {
+	case "historical":
+		return Quantile(R, alpha)
+	case "gaussian":
+		return stat.Mean(R, nil) + distuv.UnitNormal.Quantile(alpha)*math.Sqrt(centralMoment(R, 2))
+	default:
+		return stat.Mean(R, nil) + cornishFisher(alpha, Skewness(R, "moment"), Kurtosis(R, "excess"))*math.Sqrt(centralMoment(R, 2))
+	}
+}
+
+// - Quantile function
+// Quantile returns the q quantile of the data with linear interpolation
+// between order statistics, the default (type 7) of the R quantile function
+func Quantile(data []float64, q float64) float64 {
+	if len(data) == 0 {
+		return math.NaN()
+	}
+	sorted := make([]float64, len(data))
+	copy(sorted, data)
+	sort.Float64s(sorted)
+	h := float64(len(sorted)-1) * q
+	lo := math.Floor(h)
+	hi := math.Ceil(h)
+	return sorted[int(lo)] + (h-lo)*(sorted[int(hi)]-sorted[int(lo)])
+}
+
+// * function for the Cornish-Fisher quantile of a standardized distribution
+// skew is the skewness and exkurt the excess kurtosis
+func cornishFisher(alpha, skew, exkurt float64) float64 {
+	z := distuv.UnitNormal.Quantile(alpha)
+	return z +
+		(z*z-1)*skew/6 +
+		(z*z*z-3*z)*exkurt/24 -
+		(2*z*z*z-5*z)*skew*skew/36
+}
+
+// * function for the central moment of order k with the 1/n denominator
+func centralMoment(data []float64, k float64) float64 {
+	mean := stat.Mean(data, nil)
+	sum := 0.0
+	for _, v := range data {
+		sum += math.Pow(v-mean, k)
+	}
+	return sum / float64(len(data))
+}
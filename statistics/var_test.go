@@ -0,0 +1,49 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/stat"
+)
+
+// Test the VaR methods on the edhec columns
+func TestVaR(t *testing.T) {
+	rf, err := ReadFrame("../data/edhec.csv")
+	if err != nil {
+		panic(err)
+	}
+	ca, _ := rf.Column("Convertible Arbitrage")
+	cta, _ := rf.Column("CTA Global")
+
+	// reference values of the PerformanceAnalytics calls
+	//   VaR(edhec[, "Convertible Arbitrage"], p = 0.95, method = "historical")
+	//   VaR(edhec[, "Convertible Arbitrage"], p = 0.99, method = "gaussian")
+	//   VaR(edhec[, "CTA Global"], p = 0.95, method = "modified")
+	// and so on; R is not available to the test suite, the numbers come from
+	// testdata/reference/var.py which follows VaR.historical, VaR.Gaussian and
+	// VaR.CornishFisher, rerun the R calls to check them
+	assert.InDelta(t, -0.0191600, VaR(ca, 0.95, "historical"), 0.0000001)
+	assert.InDelta(t, -0.0665920, VaR(ca, 0.99, "historical"), 0.0000001)
+	assert.InDelta(t, -0.0264578158, VaR(ca, 0.95, "gaussian"), 0.0000000001)
+	assert.InDelta(t, -0.0400749794, VaR(ca, 0.99, "gaussian"), 0.0000000001)
+	assert.InDelta(t, -0.0324739478, VaR(ca, 0.95, "modified"), 0.0000000001)
+	assert.InDelta(t, -0.1009222712, VaR(ca, 0.99, "modified"), 0.0000000001)
+	assert.InDelta(t, -0.0354000, VaR(cta, 0.95, "historical"), 0.0000001)
+	assert.InDelta(t, -0.0347109783, VaR(cta, 0.95, "gaussian"), 0.0000000001)
+	assert.InDelta(t, -0.0338022810, VaR(cta, 0.95, "modified"), 0.0000000001)
+
+	// the Cornish-Fisher expansion without skewness and excess kurtosis is gaussian
+	assert.InDelta(t, VaR(cta, 0.99, "gaussian"),
+		stat.Mean(cta, nil)+cornishFisher(0.01, 0, 0)*math.Sqrt(centralMoment(cta, 2)), 1e-15)
+
+	// modified is the default
+	assert.Equal(t, VaR(ca, 0.95, "modified"), VaR(ca, 0.95, ""))
+	assert.True(t, math.IsNaN(VaR(ca, 1.0, "gaussian")))
+	assert.True(t, math.IsNaN(VaR(nil, 0.95, "historical")))
+
+	// the quantile matches R type 7
+	assert.InDelta(t, 2.5, Quantile([]float64{4, 1, 3, 2}, 0.5), 0.0000001)
+	assert.InDelta(t, 1.3, Quantile([]float64{4, 1, 3, 2}, 0.1), 0.0000001)
+}
@@ -0,0 +1,139 @@
+package statistics
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// - define the Weighted Least Squares model
+// WLS minimizes sum w_i * e_i^2, it shares the accessors, the diagnostics
+// and the summaries of OLS through the embedded model
+// the R-squared is weighted around the weighted mean of Y, as statsmodels
+type WLS struct {
+	*OLS
+}
+
+// NewWLS creates a new Weighted Least Squares model, one positive weight per row of X
+func NewWLS(X, Y *mat.Dense, weights []float64, opts ...OptionOLS) *WLS {
+	ols := NewOLS(X, Y, opts...)
+	ols.weights = append([]float64(nil), weights...)
+	ols.model = "WLS"
+	return &WLS{OLS: ols}
+}
+
+// method to get the observation weights
+func (wls *WLS) Weights() []float64 {
+	return wls.weights
+}
+
+// - ExponentialWeights function
+// ExponentialWeights gives n weights halving every halfLife periods,
+// the last (most recent) observation has weight 1
+func ExponentialWeights(n int, halfLife float64) []float64 {
+	weights := make([]float64, n)
+	for t := range weights {
+		weights[t] = math.Pow(0.5, float64(n-1-t)/halfLife)
+	}
+	return weights
+}
+
+// - define the feasible GLS model with AR(1) errors
+// e_t = rho * e_(t-1) + u_t, estimated by iterated Cochrane-Orcutt:
+// regress y_t - rho * y_(t-1) on x_t - rho * x_(t-1), estimate rho again from the
+// residuals Y - X * beta, until rho settles
+// the embedded OLS is the last regression on the transformed data, so its
+// residuals, R-squared and diagnostics are those of the transformed model
+// (n - 1 observations), as the GLSAR results of statsmodels; before the fit
+// it is the unfitted OLS of Y on X, so the accessors do not panic
+type GLSAR struct {
+	*OLS
+	// Rho is the AR(1) coefficient of the errors used by the last regression
+	Rho float64
+	// Iterations is the number of regressions run
+	Iterations int
+
+	x, y *mat.Dense
+	opts []OptionOLS
+}
+
+// the defaults of GLSAR.Run
+const (
+	GLSARMaxIter = 100
+	GLSARTol     = 1e-8
+)
+
+// NewGLSAR creates a new feasible GLS model with AR(1) errors
+func NewGLSAR(X, Y *mat.Dense, opts ...OptionOLS) *GLSAR {
+	ols := NewOLS(X, Y, opts...)
+	ols.model = "GLSAR"
+	return &GLSAR{OLS: ols, x: X, y: Y, opts: opts}
+}
+
+// method Run iterates with the defaults GLSARMaxIter and GLSARTol
+func (g *GLSAR) Run() error {
+	return g.IterativeFit(GLSARMaxIter, GLSARTol)
+}
+
+// method IterativeFit runs at most maxIter regressions, stopping when
+// rho moves by less than tol; it returns a *ConvergenceError when rho still
+// moves after maxIter regressions, the model then holds the last one
+func (g *GLSAR) IterativeFit(maxIter int, tol float64) error {
+	n, _ := g.x.Dims()
+	if n < 3 {
+		return errors.New("glsar: need at least 3 observations")
+	}
+	rho := 0.0
+	for it := 1; it <= maxIter; it++ {
+		ols := NewOLS(arDifference(g.x, rho), arDifference(g.y, rho), g.opts...)
+		ols.model = "GLSAR"
+		if err := ols.Run(); err != nil {
+			return err
+		}
+		g.OLS, g.Rho, g.Iterations = ols, rho, it
+
+		// the residuals of the untransformed model give the next rho
+		var resid mat.Dense
+		resid.Mul(g.x, ols.coefficients)
+		resid.Sub(g.y, &resid)
+		next := ar1YuleWalker(mat.Col(nil, 0, &resid))
+		if math.Abs(next-rho) < tol {
+			return nil
+		}
+		rho = next
+	}
+	return &ConvergenceError{What: "glsar", Iterations: maxIter}
+}
+
+// * function for the quasi differences m_t - rho * m_(t-1), dropping the first row
+func arDifference(m *mat.Dense, rho float64) *mat.Dense {
+	r, c := m.Dims()
+	out := mat.NewDense(r-1, c, nil)
+	for i := 1; i < r; i++ {
+		for j := 0; j < c; j++ {
+			out.Set(i-1, j, m.At(i, j)-rho*m.At(i-1, j))
+		}
+	}
+	return out
+}
+
+// * function for the Yule-Walker AR(1) coefficient of a demeaned series
+// the lag one autocovariance over n - 1 divided by the variance over n,
+// the adjusted method of statsmodels yule_walker
+func ar1YuleWalker(e []float64) float64 {
+	n := float64(len(e))
+	mean := 0.0
+	for _, v := range e {
+		mean += v
+	}
+	mean /= n
+	r0, r1 := 0.0, 0.0
+	for t := range e {
+		r0 += (e[t] - mean) * (e[t] - mean)
+		if t > 0 {
+			r1 += (e[t] - mean) * (e[t-1] - mean)
+		}
+	}
+	return (r1 / (n - 1)) / (r0 / n)
+}
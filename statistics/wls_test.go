@@ -0,0 +1,96 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test WLS with exponential weights on data.csv
+// expected values of the statsmodels call
+//
+//	WLS(y, X, weights=0.5 ** ((n - 1 - t) / 12)).fit()
+//
+// from testdata/reference/wls.py as statsmodels is not available to the test suite
+func TestWLS(t *testing.T) {
+	X, Y := ReadCSV("data.csv", true, "index_price", "interest_rate", "unemployment_rate")
+	weights := ExponentialWeights(24, 12)
+	assert.InDelta(t, 1.0, weights[23], 1e-15)
+	assert.InDelta(t, 0.2648657736, weights[0], 1e-10)
+
+	wls := NewWLS(X, Y, weights)
+	assert.NoError(t, wls.Run())
+	coef := []float64{1960.82139843, 340.90193039, -277.79557807}
+	se := []float64{1039.40173951, 132.96833244, 135.29976914}
+	for i := range coef {
+		assert.InDelta(t, coef[i], wls.Coefficients().At(i, 0), 1e-6)
+		assert.InDelta(t, se[i], wls.StandardErrors().At(i, 0), 1e-6)
+	}
+	assert.InDelta(t, 0.8522915143, wls.RSquared(), 1e-9)
+	assert.InDelta(t, 0.8382240395, wls.AdjRSquared(), 1e-9)
+	assert.InDelta(t, -138.71768376, wls.LogLikelihood(), 1e-6)
+	assert.InDelta(t, 60.58596335, wls.FStatistic(), 1e-6)
+	summary, err := wls.Summary()
+	assert.NoError(t, err)
+	assert.Contains(t, summary, "WLS Regression Results")
+
+	// the residuals are on the scale of Y, the whitened ones scaled by sqrt(w)
+	assert.InDelta(t, Y.At(0, 0)-wls.YHat().At(0, 0), wls.Residuals().At(0, 0), 1e-9)
+	assert.InDelta(t, wls.Residuals().At(0, 0)*math.Sqrt(weights[0]), wls.WResiduals().At(0, 0), 1e-9)
+
+	// unit weights give OLS
+	ones := make([]float64, 24)
+	for i := range ones {
+		ones[i] = 1
+	}
+	unit := NewWLS(X, Y, ones)
+	assert.NoError(t, unit.Run())
+	assert.InDelta(t, 345.5400870107158, unit.Coefficients().At(1, 0), 1e-6)
+	assert.InDelta(t, 0.8976335894170216, unit.RSquared(), 1e-9)
+
+	var lme *LengthMismatchError
+	assert.ErrorAs(t, NewWLS(X, Y, ones[:10]).Run(), &lme)
+}
+
+// test GLSAR with AR(1) errors on data.csv
+// expected values of the statsmodels call
+//
+//	GLSAR(y, X, rho=1).iterative_fit(maxiter=100, rtol=1e-8)
+//
+// from testdata/reference/wls.py as statsmodels is not available to the test suite
+func TestGLSAR(t *testing.T) {
+	X, Y := ReadCSV("data.csv", true, "index_price", "interest_rate", "unemployment_rate")
+	g := NewGLSAR(X, Y)
+	assert.NoError(t, g.Run())
+	assert.InDelta(t, 0.8347560209, g.Rho, 1e-7)
+	assert.Equal(t, 35, g.Iterations)
+	coef := []float64{887.84837564, 185.27310858, -56.34063413}
+	se := []float64{443.61516462, 94.47243849, 68.17795001}
+	for i := range coef {
+		assert.InDelta(t, coef[i], g.Coefficients().At(i, 0), 1e-4)
+		assert.InDelta(t, se[i], g.StandardErrors().At(i, 0), 1e-4)
+	}
+	assert.InDelta(t, 0.1836750501, g.RSquared(), 1e-7)
+	assert.Equal(t, 23, g.N())
+	summary, err := g.Summary()
+	assert.NoError(t, err)
+	assert.Contains(t, summary, "GLSAR Regression Results")
+
+	// the accessors of an unfitted model do not panic
+	one := NewGLSAR(X, Y)
+	assert.Equal(t, 24, one.N())
+	assert.Nil(t, one.Coefficients())
+	_, err = one.Summary()
+	assert.Error(t, err)
+
+	// a single iteration is OLS without the first observation, rho has
+	// not settled and the cap is reported
+	err = one.IterativeFit(1, 1e-8)
+	var ce *ConvergenceError
+	assert.ErrorAs(t, err, &ce)
+	assert.Equal(t, 1, ce.Iterations)
+	assert.Equal(t, 0.0, one.Rho)
+	assert.Equal(t, 1, one.Iterations)
+	assert.Equal(t, 23, one.N())
+}